@@ -4,41 +4,224 @@
 package v1alpha1
 
 import (
-	"sync"
 	"time"
 )
 
+const (
+	// Group and version of the Kubernetes APIs served by Hitman CRDs
+	Group   = "hitman.io"
+	Version = "v1alpha1"
+
+	// Kinds of the Kubernetes APIs served by Hitman CRDs
+	KindHitman       = "Hitman"
+	KindHitmanPolicy = "HitmanPolicy"
+
+	// Resources of the Kubernetes APIs served by Hitman CRDs
+	ResourceHitman       = "hitmen"
+	ResourceHitmanPolicy = "hitmanpolicies"
+)
+
 var (
 	DefaultSyncTime            = "5m"
 	DefaultSyncProcessingDelay = "200ms"
+	DefaultOwnersMaxDepth      = 10
+
+	// DefaultPolicyServiceAccountName is the ServiceAccount impersonated to process the rules of a HitmanPolicy
+	// when it does not define one. It is looked up in the namespace of the policy
+	DefaultPolicyServiceAccountName = "hitman"
+
+	// DefaultClusterName is the name of the cluster where Hitman runs. It is the only one processed
+	// when the config does not declare clusters
+	DefaultClusterName = "default"
+
+	DefaultAuditFileMaxSizeMegabytes = 100
+	DefaultAuditFileMaxBackups       = 5
+	DefaultAuditWebhookTimeout       = "5s"
+
+	DefaultNotificationsRetries      = 3
+	DefaultNotificationsRetryBackoff = "1s"
+	DefaultNotificationsTimeout      = "10s"
+)
+
+const (
+	// OwnersActOnObject means actions are performed over the targeted object itself
+	OwnersActOnObject = "object"
+
+	// OwnersActOnTopController means actions are performed over the top-level controller
+	// found walking the ownerReferences of the targeted object
+	OwnersActOnTopController = "topController"
+)
+
+const (
+	// Types of the sinks where audit records are written
+	AuditSinkTypeFile    = "file"
+	AuditSinkTypeStdout  = "stdout"
+	AuditSinkTypeWebhook = "webhook"
+)
+
+const (
+	// Places where objects are backed up before their deletion
+	BackupTypeDirectory = "directory"
+	BackupTypeConfigMap = "configMap"
+	BackupTypeSecret    = "secret"
+)
+
+const (
+	// Types of the receivers where notifications are sent
+	NotificationReceiverTypeWebhook      = "webhook"
+	NotificationReceiverTypeSlack        = "slack"
+	NotificationReceiverTypeAlertmanager = "alertmanager"
+)
+
+const (
+	// CondemnedAtLabel is set on objects condemned by rules with quarantine. Its value is a Unix timestamp
+	CondemnedAtLabel = "hitman.io/condemned-at"
+
+	// VetoAnnotation can be set on objects by their owners to prevent Hitman from killing them
+	VetoAnnotation = "hitman.io/veto"
 )
 
 // TargetNameT defines TODO
 type TargetSelectorT struct {
+	// MatchExact selects objects whose value is exactly this one
 	MatchExact string `yaml:"matchExact,omitempty"`
+
+	// MatchRegex selects objects whose value matches this regular expression
 	MatchRegex string `yaml:"matchRegex,omitempty"`
 }
 
 // TargetT defines TODO
 type TargetT struct {
-	Group     string          `yaml:"group"`
-	Version   string          `yaml:"version"`
-	Resource  string          `yaml:"resource"`
+	// Group, version and resource of the targeted objects, as in the Kubernetes API (e.g. apps, v1, deployments)
+	Group    string `yaml:"group"`
+	Version  string `yaml:"version"`
+	Resource string `yaml:"resource"`
+
+	// Name and namespace of the targeted objects. Only one of matchExact and matchRegex can be set on each of them
 	Name      TargetSelectorT `yaml:"name"`
 	Namespace TargetSelectorT `yaml:"namespace"`
 }
 
 // ConditionT defines TODO
 type ConditionT struct {
+	// Key is a template evaluated for each object. The condition is met when its result equals the value
 	Key   string `yaml:"key"`
 	Value string `yaml:"value"`
 }
 
+// OwnersT defines how the owners of targeted objects are taken into account
+type OwnersT struct {
+	// Resolve the ownerReferences chain and expose it to templates as .owners
+	Resolve bool `yaml:"resolve,omitempty"`
+
+	// MaxDepth is the maximum number of owners walked up the chain (Default: 10)
+	MaxDepth int `yaml:"maxDepth,omitempty"`
+
+	// ActOn defines which object is killed when the conditions are met: 'object' or 'topController'
+	ActOn string `yaml:"actOn,omitempty"`
+
+	// HealthyConditions are evaluated before the conditions with the direct owner available as .owner
+	// When all of them are met, the owner is considered healthy and the object is skipped
+	HealthyConditions []ConditionT `yaml:"healthyConditions,omitempty"`
+}
+
+// ServiceAccountReferenceT defines a reference to a ServiceAccount
+type ServiceAccountReferenceT struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+}
+
+// ImpersonateT defines the identity used to process a rule.
+// A user (with optional groups) or a ServiceAccount can be impersonated, but not both of them
+type ImpersonateT struct {
+	User           string                   `yaml:"user,omitempty"`
+	Groups         []string                 `yaml:"groups,omitempty"`
+	ServiceAccount ServiceAccountReferenceT `yaml:"serviceAccount,omitempty"`
+}
+
+// WindowT defines a daily time window, optionally restricted to some days of the week (Mon, Tue...).
+// Times are expressed as HH:MM. When the end is before the start, the window crosses midnight
+type WindowT struct {
+	Days  []string `yaml:"days,omitempty"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
+}
+
+// QuarantineT defines a two-phase kill: objects meeting the conditions are condemned first,
+// and only killed when they still meet them after the grace period
+type QuarantineT struct {
+	// GracePeriod is the time objects stay condemned before being killed (e.g. 1h)
+	GracePeriod string `yaml:"gracePeriod"`
+
+	// Carried stuff
+	CarriedGracePeriod time.Duration `yaml:"-"`
+}
+
+// BackupT defines where objects are backed up before their deletion.
+// Directories are local to Hitman. ConfigMaps and Secrets are created in the cluster of the object
+type BackupT struct {
+	// Type of the backup: 'directory', 'configMap' or 'secret'
+	Type string `yaml:"type"`
+
+	// Path of the directory, for 'directory' backups
+	Path string `yaml:"path,omitempty"`
+
+	// Namespace where ConfigMaps and Secrets are created. Required for those types
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// SourceT defines the Kubernetes object a resource was read from when the config comes from CRDs
+type SourceT struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Position of the resource inside the object
+	Index int
+}
+
 // ResourceT defines TODO
 type ResourceT struct {
-	Target     TargetT      `yaml:"target"`
-	PreStep    string       `yaml:"preStep,omitempty"`
+	// Name identifies the rule in logs, metrics, events and status. It must be unique (Default: its position)
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	// Labels are added to events, audit records and alerts, and can be used to select rules
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Disabled rules are kept in the config but never processed (Default: true)
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// DryRun overrides the global --dry-run flag for this rule. Rules not setting it follow the flag
+	DryRun *bool `yaml:"dryRun,omitempty"`
+
+	Target TargetT `yaml:"target"`
+
+	// Schedule is a cron expression or an interval. Synchronization time is used when it is empty.
+	// Rules only run inside their active windows (when defined), and never inside their blackout windows.
+	// Timezone applies to the schedule and the windows (Default: local)
+	Schedule        string    `yaml:"schedule,omitempty"`
+	Timezone        string    `yaml:"timezone,omitempty"`
+	ActiveWindows   []WindowT `yaml:"activeWindows,omitempty"`
+	BlackoutWindows []WindowT `yaml:"blackoutWindows,omitempty"`
+
+	// Clusters where the rule is processed. Empty means all of them
+	Clusters []string `yaml:"clusters,omitempty"`
+
+	Owners      OwnersT      `yaml:"owners,omitempty"`
+	Impersonate ImpersonateT `yaml:"impersonate,omitempty"`
+	Quarantine  QuarantineT  `yaml:"quarantine,omitempty"`
+	Backup      BackupT      `yaml:"backup,omitempty"`
+
+	// PreStep is a template evaluated once before the conditions, with all the targets available as .targets.
+	// Variables stored with setVar are available in the conditions as .vars
+	PreStep string `yaml:"preStep,omitempty"`
+
+	// Conditions to kill an object. All of them must be met
 	Conditions []ConditionT `yaml:"conditions"`
+
+	// Carried stuff
+	CarriedSource SourceT `yaml:"-"`
 }
 
 // MetadataSpec TODO
@@ -48,27 +231,180 @@ type MetadataT struct {
 
 // SynchronizationT defines TODO
 type SynchronizationT struct {
-	Time            string `yaml:"time"`
+	// Time between runs of the rules without schedule (Default: 5m)
+	Time string `yaml:"time"`
+
+	// ProcessingDelay is the time waited between the rules processed on each run (Default: 200ms)
 	ProcessingDelay string `yaml:"processingDelay,omitempty"`
 
 	// Carried stuff
-	CarriedTime            time.Duration
-	CarriedProcessingDelay time.Duration
+	CarriedTime            time.Duration `yaml:"-"`
+	CarriedProcessingDelay time.Duration `yaml:"-"`
+}
+
+// SecretKeyReferenceT defines a reference to a key inside a Secret
+type SecretKeyReferenceT struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	Key       string `yaml:"key,omitempty"`
+}
+
+// ClusterT defines a Kubernetes cluster where rules are processed.
+// Credentials are read from a kubeconfig file or from a Secret in the cluster where Hitman runs.
+// When none of them is set, the credentials of Hitman itself are used
+type ClusterT struct {
+	Name       string              `yaml:"name"`
+	Kubeconfig string              `yaml:"kubeconfig,omitempty"`
+	Context    string              `yaml:"context,omitempty"`
+	SecretRef  SecretKeyReferenceT `yaml:"secretRef,omitempty"`
+}
+
+// KubernetesClientT defines how Hitman connects to Kubernetes.
+// Kubeconfig and context select the cluster where Hitman runs. The rest of the settings tune the clients of all clusters
+type KubernetesClientT struct {
+	Kubeconfig string  `yaml:"kubeconfig,omitempty"`
+	Context    string  `yaml:"context,omitempty"`
+	QPS        float32 `yaml:"qps,omitempty"`
+	Burst      int     `yaml:"burst,omitempty"`
+	Timeout    string  `yaml:"timeout,omitempty"`
+	UserAgent  string  `yaml:"userAgent,omitempty"`
+
+	// Carried stuff
+	CarriedTimeout time.Duration `yaml:"-"`
+}
+
+// AuditFileSinkT defines a file where audit records are written as JSON lines.
+// The file is rotated when it reaches its maximum size
+type AuditFileSinkT struct {
+	Path             string `yaml:"path"`
+	MaxSizeMegabytes int    `yaml:"maxSizeMegabytes,omitempty"`
+	MaxBackups       int    `yaml:"maxBackups,omitempty"`
+}
+
+// AuditWebhookSinkT defines an HTTP endpoint where audit records are sent as JSON
+type AuditWebhookSinkT struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Timeout string            `yaml:"timeout,omitempty"`
+}
+
+// AuditSinkT defines a place where audit records are written. Only the settings of its type are used
+type AuditSinkT struct {
+	// Type of the sink: 'file', 'stdout' or 'webhook'
+	Type    string            `yaml:"type"`
+	File    AuditFileSinkT    `yaml:"file,omitempty"`
+	Webhook AuditWebhookSinkT `yaml:"webhook,omitempty"`
+}
+
+// AuditT defines where the actions performed over objects are recorded
+type AuditT struct {
+	// IncludeObject adds a full snapshot of the object before its deletion to the records
+	IncludeObject bool         `yaml:"includeObject,omitempty"`
+	Sinks         []AuditSinkT `yaml:"sinks,omitempty"`
+}
+
+// NotificationWebhookT defines an HTTP endpoint where notifications are sent.
+// Body is a template evaluated with the events of the loop available as .events
+type NotificationWebhookT struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+}
+
+// NotificationSlackT defines a Slack-compatible incoming webhook.
+// Text is a template evaluated with the events of the loop available as .events
+type NotificationSlackT struct {
+	URL  string `yaml:"url"`
+	Text string `yaml:"text,omitempty"`
+}
+
+// NotificationAlertmanagerT defines an Alertmanager where one alert is fired per event
+type NotificationAlertmanagerT struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+// NotificationReceiverT defines a place where notifications are sent. Only the settings of its type are used
+type NotificationReceiverT struct {
+	Name string `yaml:"name,omitempty"`
+
+	// Type of the receiver: 'webhook', 'slack' or 'alertmanager'
+	Type         string                    `yaml:"type"`
+	Webhook      NotificationWebhookT      `yaml:"webhook,omitempty"`
+	Slack        NotificationSlackT        `yaml:"slack,omitempty"`
+	Alertmanager NotificationAlertmanagerT `yaml:"alertmanager,omitempty"`
+}
+
+// NotificationsT defines where the actions performed on each loop are notified.
+// Actions are batched, so each receiver is notified once per loop
+type NotificationsT struct {
+	Retries      int                     `yaml:"retries,omitempty"`
+	RetryBackoff string                  `yaml:"retryBackoff,omitempty"`
+	Timeout      string                  `yaml:"timeout,omitempty"`
+	Receivers    []NotificationReceiverT `yaml:"receivers,omitempty"`
 }
 
 // SpecificationSpec TODO
 type SpecificationT struct {
-	Synchronization SynchronizationT `yaml:"synchronization"`
-	Resources       []ResourceT      `yaml:"resources"`
+	Synchronization SynchronizationT  `yaml:"synchronization"`
+	Kubernetes      KubernetesClientT `yaml:"kubernetes,omitempty"`
+	Clusters        []ClusterT        `yaml:"clusters,omitempty"`
+	Audit           AuditT            `yaml:"audit,omitempty"`
+	Notifications   NotificationsT    `yaml:"notifications,omitempty"`
+
+	// Templates are named snippets callable from any preStep or condition with 'include', like in Helm
+	Templates map[string]string `yaml:"templates,omitempty"`
+
+	Resources []ResourceT `yaml:"resources"`
+}
+
+// PolicySpecificationT defines the specification of a namespaced policy.
+// Synchronization settings are only defined at cluster level.
+// Targets are confined to the namespace of the policy, and processed impersonating a ServiceAccount of that namespace
+type PolicySpecificationT struct {
+	// ServiceAccountName is the ServiceAccount impersonated to process the rules (Default: hitman)
+	ServiceAccountName string `yaml:"serviceAccountName,omitempty"`
+
+	Resources []ResourceT `yaml:"resources"`
+}
+
+// RuleStatusT defines the result of the last run of a rule
+type RuleStatusT struct {
+	Name      string `yaml:"name" json:"name"`
+	Cluster   string `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+	LastRun   string `yaml:"lastRun,omitempty" json:"lastRun,omitempty"`
+	Matched   int    `yaml:"matched" json:"matched"`
+	Killed    int    `yaml:"killed" json:"killed"`
+	Condemned int    `yaml:"condemned,omitempty" json:"condemned,omitempty"`
+	Errors    int    `yaml:"errors" json:"errors"`
+	LastError string `yaml:"lastError,omitempty" json:"lastError,omitempty"`
+
+	// Rules in dry-run (shadow) mode never touch objects. They report what they would have killed or condemned instead
+	DryRun       bool `yaml:"dryRun,omitempty" json:"dryRun,omitempty"`
+	WouldKill    int  `yaml:"wouldKill,omitempty" json:"wouldKill,omitempty"`
+	WouldCondemn int  `yaml:"wouldCondemn,omitempty" json:"wouldCondemn,omitempty"`
+}
+
+// StatusT defines the status reported into Hitman and HitmanPolicy objects
+type StatusT struct {
+	LastRun string        `yaml:"lastRun,omitempty" json:"lastRun,omitempty"`
+	Rules   []RuleStatusT `yaml:"rules,omitempty" json:"rules,omitempty"`
+
+	// Error found in the spec of the object. Its rules are not processed until it is fixed
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
 }
 
 // ConfigSpec TODO
 type ConfigT struct {
-	Mutex sync.RWMutex
-
 	//
-	ApiVersion string         `yaml:"apiVersion"`
-	Kind       string         `yaml:"kind"`
-	Metadata   MetadataT      `yaml:"metadata"`
-	Spec       SpecificationT `yaml:"spec"`
+	ApiVersion string    `yaml:"apiVersion"`
+	Kind       string    `yaml:"kind"`
+	Metadata   MetadataT `yaml:"metadata"`
+
+	// Include other config files (paths, globs or directories) relative to this one.
+	// Their resources are merged into this config
+	Include []string `yaml:"include,omitempty"`
+
+	Spec SpecificationT `yaml:"spec"`
 }
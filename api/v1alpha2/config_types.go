@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	"hitman/api/v1alpha1"
+)
+
+const (
+	// Group and kind are the same as in v1alpha1. Only the version changes
+	Group      = v1alpha1.Group
+	Version    = "v1alpha2"
+	KindHitman = v1alpha1.KindHitman
+)
+
+// Types not changed since v1alpha1 are shared with it
+type (
+	MetadataT         = v1alpha1.MetadataT
+	SynchronizationT  = v1alpha1.SynchronizationT
+	KubernetesClientT = v1alpha1.KubernetesClientT
+	ClusterT          = v1alpha1.ClusterT
+	AuditT            = v1alpha1.AuditT
+	NotificationsT    = v1alpha1.NotificationsT
+	RuleT             = v1alpha1.ResourceT
+)
+
+// SpecificationT defines the behavior of Hitman.
+// Compared to v1alpha1, 'resources' are called 'rules', as they are referred to everywhere else
+type SpecificationT struct {
+	Synchronization SynchronizationT  `yaml:"synchronization"`
+	Kubernetes      KubernetesClientT `yaml:"kubernetes,omitempty"`
+	Clusters        []ClusterT        `yaml:"clusters,omitempty"`
+	Audit           AuditT            `yaml:"audit,omitempty"`
+	Notifications   NotificationsT    `yaml:"notifications,omitempty"`
+
+	// Templates are named snippets callable from any preStep or condition with 'include', like in Helm
+	Templates map[string]string `yaml:"templates,omitempty"`
+
+	Rules []RuleT `yaml:"rules"`
+}
+
+// ConfigT defines a config file in v1alpha2
+type ConfigT struct {
+	ApiVersion string    `yaml:"apiVersion"`
+	Kind       string    `yaml:"kind"`
+	Metadata   MetadataT `yaml:"metadata"`
+
+	// Include other config files (paths, globs or directories) relative to this one.
+	// Their rules are merged into this config
+	Include []string `yaml:"include,omitempty"`
+
+	Spec SpecificationT `yaml:"spec"`
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	"hitman/api/v1alpha1"
+)
+
+// ConvertFromV1alpha1 converts a v1alpha1 config into v1alpha2
+func ConvertFromV1alpha1(in *v1alpha1.ConfigT) *ConfigT {
+	return &ConfigT{
+		ApiVersion: Group + "/" + Version,
+		Kind:       in.Kind,
+		Metadata:   in.Metadata,
+		Include:    in.Include,
+		Spec: SpecificationT{
+			Synchronization: in.Spec.Synchronization,
+			Kubernetes:      in.Spec.Kubernetes,
+			Clusters:        in.Spec.Clusters,
+			Audit:           in.Spec.Audit,
+			Notifications:   in.Spec.Notifications,
+			Templates:       in.Spec.Templates,
+			Rules:           in.Spec.Resources,
+		},
+	}
+}
+
+// ConvertToV1alpha1 converts a v1alpha2 config into v1alpha1.
+// Hitman works internally with v1alpha1 types, as they are the ones served by the CRDs
+func ConvertToV1alpha1(in *ConfigT) *v1alpha1.ConfigT {
+	return &v1alpha1.ConfigT{
+		ApiVersion: v1alpha1.Group + "/" + v1alpha1.Version,
+		Kind:       in.Kind,
+		Metadata:   in.Metadata,
+		Include:    in.Include,
+		Spec: v1alpha1.SpecificationT{
+			Synchronization: in.Spec.Synchronization,
+			Kubernetes:      in.Spec.Kubernetes,
+			Clusters:        in.Spec.Clusters,
+			Audit:           in.Spec.Audit,
+			Notifications:   in.Spec.Notifications,
+			Templates:       in.Spec.Templates,
+			Resources:       in.Spec.Rules,
+		},
+	}
+}
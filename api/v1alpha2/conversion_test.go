@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	"reflect"
+	"testing"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+func TestConversionRoundTrip(t *testing.T) {
+	enabled := false
+
+	config := &v1alpha1.ConfigT{
+		ApiVersion: v1alpha1.Group + "/" + v1alpha1.Version,
+		Kind:       v1alpha1.KindHitman,
+		Metadata:   v1alpha1.MetadataT{Name: "main"},
+		Include:    []string{"./teams"},
+		Spec: v1alpha1.SpecificationT{
+			Synchronization: v1alpha1.SynchronizationT{Time: "1m"},
+			Clusters:        []v1alpha1.ClusterT{{Name: "default"}},
+			Templates:       map[string]string{"old": `{{ .object | olderThan "1h" }}`},
+			Resources: []v1alpha1.ResourceT{
+				{Name: "a", Enabled: &enabled},
+				{Name: "b", Schedule: "0 2 * * *"},
+			},
+		},
+	}
+
+	converted := ConvertFromV1alpha1(config)
+	if converted.ApiVersion != Group+"/"+Version {
+		t.Fatalf("got apiVersion '%s', want '%s'", converted.ApiVersion, Group+"/"+Version)
+	}
+
+	if !reflect.DeepEqual(converted.Spec.Rules, config.Spec.Resources) {
+		t.Fatalf("got rules %v, want the resources %v", converted.Spec.Rules, config.Spec.Resources)
+	}
+
+	if roundTrip := ConvertToV1alpha1(converted); !reflect.DeepEqual(roundTrip, config) {
+		t.Fatalf("got %+v after the round trip, want %+v", roundTrip, config)
+	}
+}
@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha2
+
+import (
+	_ "embed"
+)
+
+// TypesSource is the source code of the types of this API.
+// Their doc comments are used as descriptions in the generated schemas
+//
+//go:embed config_types.go
+var TypesSource string
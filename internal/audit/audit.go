@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+var (
+	// Auditor used by the whole application. It is rebuilt when the audit settings change
+	defaultAuditor      = &Auditor{}
+	defaultAuditorMutex sync.Mutex
+)
+
+// ConditionResultT defines the result of evaluating a condition against an object
+type ConditionResultT struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Result string `json:"result"`
+	Met    bool   `json:"met"`
+}
+
+// EventT defines a record of an action performed over an object
+type EventT struct {
+	Timestamp  string            `json:"timestamp"`
+	Cluster    string            `json:"cluster"`
+	Rule       string            `json:"rule"`
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
+	Identity   string            `json:"identity,omitempty"`
+
+	// Resource targeted by the rule
+	GVR string `json:"gvr"`
+
+	// Object the action was performed over. It can be the top-level controller of the targeted one
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+
+	Action  string `json:"action"`
+	Outcome string `json:"outcome"`
+	DryRun  bool   `json:"dryRun"`
+	Error   string `json:"error,omitempty"`
+
+	Conditions []ConditionResultT `json:"conditions,omitempty"`
+
+	// Full snapshot of the object before the action, only when requested
+	Object map[string]interface{} `json:"object,omitempty"`
+}
+
+// Sink is a place where audit records are written
+type Sink interface {
+	Write(event EventT) error
+	Close() error
+}
+
+// Auditor writes audit records into all the sinks defined in the config
+type Auditor struct {
+	config v1alpha1.AuditT
+	sinks  []Sink
+}
+
+// NewAuditor return an auditor writing into the sinks defined in the config.
+// Sinks that can not be created are returned as errors, and the rest of them are still used
+func NewAuditor(config v1alpha1.AuditT) (auditor *Auditor, errs []error) {
+	auditor = &Auditor{config: config}
+
+	for sinkIndex, sinkConfig := range config.Sinks {
+		sink, err := newSink(sinkConfig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error creating audit sinks[%d]: %s", sinkIndex, err.Error()))
+			continue
+		}
+		auditor.sinks = append(auditor.sinks, sink)
+	}
+
+	return auditor, errs
+}
+
+// newSink return a sink of the type defined in the config
+func newSink(config v1alpha1.AuditSinkT) (sink Sink, err error) {
+	switch config.Type {
+	case v1alpha1.AuditSinkTypeFile:
+		return newFileSink(config.File)
+	case v1alpha1.AuditSinkTypeStdout:
+		return newStdoutSink(), nil
+	case v1alpha1.AuditSinkTypeWebhook:
+		return newWebhookSink(config.Webhook)
+	}
+
+	return sink, fmt.Errorf("type must be '%s', '%s' or '%s'",
+		v1alpha1.AuditSinkTypeFile, v1alpha1.AuditSinkTypeStdout, v1alpha1.AuditSinkTypeWebhook)
+}
+
+// Enabled return true when there is, at least, one sink to write into
+func (a *Auditor) Enabled() bool {
+	return len(a.sinks) > 0
+}
+
+// Record writes an event into all the sinks. Failing sinks do not prevent the rest of them from being written
+func (a *Auditor) Record(event EventT) {
+	if !a.config.IncludeObject {
+		event.Object = nil
+	}
+
+	for _, sink := range a.sinks {
+		err := sink.Write(event)
+		if err != nil {
+			globals.ExecContext.Logger.Infow("error writing audit record",
+				"rule", event.Rule, "namespace", event.Namespace, "name", event.Name, "error", err.Error())
+		}
+	}
+}
+
+// Close releases the resources of all the sinks
+func (a *Auditor) Close() {
+	for _, sink := range a.sinks {
+		err := sink.Close()
+		if err != nil {
+			globals.ExecContext.Logger.Infof("error closing audit sink: %s", err.Error())
+		}
+	}
+}
+
+// Configure rebuilds the auditor used by the whole application when the audit settings change
+func Configure(config v1alpha1.AuditT) {
+	defaultAuditorMutex.Lock()
+	defer defaultAuditorMutex.Unlock()
+
+	if reflect.DeepEqual(defaultAuditor.config, config) {
+		return
+	}
+
+	auditor, errs := NewAuditor(config)
+	for _, err := range errs {
+		globals.ExecContext.Logger.Info(err.Error())
+	}
+
+	defaultAuditor.Close()
+	defaultAuditor = auditor
+}
+
+// Enabled return true when the auditor used by the whole application has, at least, one sink to write into
+func Enabled() bool {
+	defaultAuditorMutex.Lock()
+	defer defaultAuditorMutex.Unlock()
+
+	return defaultAuditor.Enabled()
+}
+
+// Record writes an event using the auditor of the whole application
+func Record(event EventT) {
+	defaultAuditorMutex.Lock()
+	defer defaultAuditorMutex.Unlock()
+
+	defaultAuditor.Record(event)
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+// fileSink writes audit records as JSON lines into a file.
+// When the file reaches its maximum size, it is rotated as <path>.1, <path>.2 and so on
+type fileSink struct {
+	path         string
+	maxSizeBytes int64
+	maxBackups   int
+
+	file *os.File
+	size int64
+}
+
+func newFileSink(config v1alpha1.AuditFileSinkT) (sink *fileSink, err error) {
+	if config.Path == "" {
+		return sink, fmt.Errorf("file path is missing")
+	}
+
+	if config.MaxSizeMegabytes <= 0 {
+		config.MaxSizeMegabytes = v1alpha1.DefaultAuditFileMaxSizeMegabytes
+	}
+
+	if config.MaxBackups <= 0 {
+		config.MaxBackups = v1alpha1.DefaultAuditFileMaxBackups
+	}
+
+	sink = &fileSink{
+		path:         config.Path,
+		maxSizeBytes: int64(config.MaxSizeMegabytes) * 1024 * 1024,
+		maxBackups:   config.MaxBackups,
+	}
+
+	return sink, sink.open()
+}
+
+// open opens the file for appending, and takes note of its current size
+func (s *fileSink) open() (err error) {
+	s.file, err = os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	s.size = fileInfo.Size()
+	return nil
+}
+
+// rotate shifts the backups one position, dropping the oldest one, and starts a new file
+func (s *fileSink) rotate() (err error) {
+	err = s.file.Close()
+	if err != nil {
+		return err
+	}
+
+	for backupIndex := s.maxBackups - 1; backupIndex > 0; backupIndex-- {
+		err = os.Rename(fmt.Sprintf("%s.%d", s.path, backupIndex), fmt.Sprintf("%s.%d", s.path, backupIndex+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	err = os.Rename(s.path, s.path+".1")
+	if err != nil {
+		return err
+	}
+
+	return s.open()
+}
+
+func (s *fileSink) Write(event EventT) (err error) {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	eventBytes = append(eventBytes, '\n')
+
+	if s.size > 0 && s.size+int64(len(eventBytes)) > s.maxSizeBytes {
+		err = s.rotate()
+		if err != nil {
+			return fmt.Errorf("error rotating audit file: %s", err.Error())
+		}
+	}
+
+	writtenBytes, err := s.file.Write(eventBytes)
+	s.size += int64(writtenBytes)
+
+	return err
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+// stdoutSink writes audit records as JSON lines into the standard output.
+// Logs are thrown into the standard error, so both of them can be collected separately
+type stdoutSink struct {
+	encoder *json.Encoder
+}
+
+func newStdoutSink() *stdoutSink {
+	return &stdoutSink{encoder: json.NewEncoder(os.Stdout)}
+}
+
+func (s *stdoutSink) Write(event EventT) error {
+	return s.encoder.Encode(event)
+}
+
+func (s *stdoutSink) Close() error {
+	return nil
+}
+
+const (
+	// Records waiting to be sent by a webhook sink. When it is full, new records are dropped
+	webhookQueueSize = 1000
+)
+
+// webhookSink sends each audit record as JSON to an HTTP endpoint.
+// Records are queued and sent in the background, so a slow endpoint never stops the processing of objects
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	queue chan EventT
+}
+
+func newWebhookSink(config v1alpha1.AuditWebhookSinkT) (sink *webhookSink, err error) {
+	if config.URL == "" {
+		return sink, fmt.Errorf("webhook url is missing")
+	}
+
+	if config.Timeout == "" {
+		config.Timeout = v1alpha1.DefaultAuditWebhookTimeout
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return sink, fmt.Errorf("unable to parse duration: %s", err.Error())
+	}
+
+	sink = &webhookSink{
+		url:     config.URL,
+		headers: config.Headers,
+		client:  &http.Client{Timeout: timeout},
+		queue:   make(chan EventT, webhookQueueSize),
+	}
+	go sink.run()
+
+	return sink, nil
+}
+
+// Write queues a record to be sent. It never waits for the endpoint
+func (s *webhookSink) Write(event EventT) (err error) {
+	select {
+	case s.queue <- event:
+		return nil
+	default:
+		return fmt.Errorf("webhook queue is full. Record was dropped")
+	}
+}
+
+// run sends the queued records, one by one, until the sink is closed
+func (s *webhookSink) run() {
+	for event := range s.queue {
+		err := s.send(event)
+		if err != nil {
+			globals.ExecContext.Logger.Infow("error writing audit record",
+				"rule", event.Rule, "namespace", event.Namespace, "name", event.Name, "error", err.Error())
+		}
+	}
+}
+
+// send performs the request of a record
+func (s *webhookSink) send(event EventT) (err error) {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(globals.ExecContext.Context, http.MethodPost, s.url, bytes.NewReader(eventBytes))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	for headerName, headerValue := range s.headers {
+		request.Header.Set(headerName, headerValue)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status code %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Close stops accepting records. Those already queued are still sent in the background
+func (s *webhookSink) Close() error {
+	close(s.queue)
+	return nil
+}
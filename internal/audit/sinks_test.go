@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	//
+	"go.uber.org/zap"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+func TestWebhookSinkDoesNotWait(t *testing.T) {
+	globals.ExecContext.Logger = *zap.NewNop().Sugar()
+
+	received := make(chan struct{}, webhookQueueSize)
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		<-release
+		received <- struct{}{}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	sink, err := newWebhookSink(v1alpha1.AuditWebhookSinkT{URL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The endpoint does not answer, but records are queued right away
+	start := time.Now()
+	for range 3 {
+		err = sink.Write(EventT{Rule: "rule"})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("writing records took %s, want them queued without waiting for the endpoint", elapsed)
+	}
+
+	// Records are dropped once the queue is full, instead of waiting for room
+	for range webhookQueueSize {
+		_ = sink.Write(EventT{Rule: "rule"})
+	}
+
+	if err = sink.Write(EventT{Rule: "rule"}); err == nil {
+		t.Fatalf("got no error writing into a full queue, want the record dropped")
+	}
+
+	release <- struct{}{}
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("queued record was never sent")
+	}
+
+	_ = sink.Close()
+}
@@ -0,0 +1,279 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	//
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+const (
+	// Label set on ConfigMaps and Secrets holding backups, so they can be found later
+	BackupLabel = "hitman.io/backup"
+
+	// Key of ConfigMaps and Secrets where the record is stored
+	recordKey = "record.yaml"
+)
+
+var (
+	configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretGVR    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+// RecordT defines the backup of an object taken before its deletion
+type RecordT struct {
+	Timestamp string `json:"timestamp"`
+	Cluster   string `json:"cluster"`
+	Rule      string `json:"rule"`
+
+	// Manifest of the object, stripped of its status and the fields managed by Kubernetes
+	Object map[string]interface{} `json:"object"`
+}
+
+// NewRecord return the backup of an object with its manifest ready to be created again
+func NewRecord(cluster, rule string, object unstructured.Unstructured) RecordT {
+	return RecordT{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Cluster:   cluster,
+		Rule:      rule,
+		Object:    GetManifest(object).Object,
+	}
+}
+
+// GetManifest return a copy of an object without the fields filled by Kubernetes.
+// Owner references are removed too, as owners are not expected to exist when the object is restored
+func GetManifest(object unstructured.Unstructured) *unstructured.Unstructured {
+	manifest := object.DeepCopy()
+
+	delete(manifest.Object, "status")
+
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields",
+		"selfLink", "deletionTimestamp", "deletionGracePeriodSeconds", "ownerReferences"} {
+		unstructured.RemoveNestedField(manifest.Object, "metadata", field)
+	}
+
+	// Restored objects must not carry an old condemnation, or they would be killed right away
+	unstructured.RemoveNestedField(manifest.Object, "metadata", "labels", v1alpha1.CondemnedAtLabel)
+	if len(manifest.GetLabels()) == 0 {
+		unstructured.RemoveNestedField(manifest.Object, "metadata", "labels")
+	}
+
+	return manifest
+}
+
+// IsSecret return true when the object is a Secret. Their backups must be stored in Secrets too,
+// as any other destination would expose their content
+func IsSecret(object map[string]interface{}) bool {
+	objectUnstructured := unstructured.Unstructured{Object: object}
+	return objectUnstructured.GetAPIVersion() == "v1" && objectUnstructured.GetKind() == "Secret"
+}
+
+// getRecordName return a name that identifies a record, and is valid for files, ConfigMaps and Secrets.
+// The kind is included, so objects of different kinds with the same name do not overwrite each other
+func getRecordName(record RecordT) string {
+	object := unstructured.Unstructured{Object: record.Object}
+	timestamp, _ := time.Parse(time.RFC3339, record.Timestamp)
+
+	nameParts := []string{"hitman-backup", fmt.Sprint(timestamp.Unix()), record.Cluster, object.GetKind(), object.GetNamespace(), object.GetName()}
+	nameParts = slices.DeleteFunc(nameParts, func(part string) bool { return part == "" })
+
+	return strings.ToLower(strings.Join(nameParts, "-"))
+}
+
+// Save stores a record where the backup config says.
+// The client is used to create ConfigMaps and Secrets in the cluster of the object
+func Save(client dynamic.Interface, config v1alpha1.BackupT, record RecordT) (err error) {
+
+	recordBytes, err := yaml.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	recordName := getRecordName(record)
+
+	if IsSecret(record.Object) && config.Type != v1alpha1.BackupTypeSecret {
+		return fmt.Errorf("secrets can only be backed up into '%s' destinations", v1alpha1.BackupTypeSecret)
+	}
+
+	switch config.Type {
+	case v1alpha1.BackupTypeDirectory:
+		if config.Path == "" {
+			return fmt.Errorf("backup path is missing")
+		}
+
+		err = os.MkdirAll(config.Path, 0750)
+		if err != nil {
+			return err
+		}
+
+		// Names only have a resolution of seconds, so a random suffix keeps records of the same object apart
+		recordFile, err := os.CreateTemp(config.Path, recordName+"-*.yaml")
+		if err != nil {
+			return err
+		}
+
+		err = recordFile.Chmod(0640)
+		if err == nil {
+			_, err = recordFile.Write(recordBytes)
+		}
+
+		if closeErr := recordFile.Close(); err == nil {
+			err = closeErr
+		}
+
+		// Partial records would break listing the rest of them
+		if err != nil {
+			_ = os.Remove(recordFile.Name())
+		}
+		return err
+
+	case v1alpha1.BackupTypeConfigMap, v1alpha1.BackupTypeSecret:
+		if config.Namespace == "" {
+			return fmt.Errorf("backup namespace is missing")
+		}
+
+		gvr, kind, dataField := configMapGVR, "ConfigMap", "data"
+		if config.Type == v1alpha1.BackupTypeSecret {
+			gvr, kind, dataField = secretGVR, "Secret", "stringData"
+		}
+
+		holder := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			dataField: map[string]interface{}{
+				recordKey: string(recordBytes),
+			},
+		}}
+
+		// Names can be truncated, so the generated suffix keeps them unique
+		holder.SetGenerateName(truncate(recordName, 57) + "-")
+		holder.SetNamespace(config.Namespace)
+		holder.SetLabels(map[string]string{BackupLabel: "true"})
+
+		_, err = client.Resource(gvr).Namespace(config.Namespace).Create(globals.ExecContext.Context, holder, v1.CreateOptions{})
+		return err
+	}
+
+	return fmt.Errorf("backup type must be '%s', '%s' or '%s'",
+		v1alpha1.BackupTypeDirectory, v1alpha1.BackupTypeConfigMap, v1alpha1.BackupTypeSecret)
+}
+
+// List return all the records stored where the backup config says, ordered by time
+func List(client dynamic.Interface, config v1alpha1.BackupT) (records []RecordT, err error) {
+
+	var recordsBytes [][]byte
+
+	switch config.Type {
+	case v1alpha1.BackupTypeDirectory:
+		filePaths, err := filepath.Glob(filepath.Join(config.Path, "hitman-backup-*.yaml"))
+		if err != nil {
+			return records, err
+		}
+
+		for _, filePath := range filePaths {
+			fileBytes, err := os.ReadFile(filePath)
+			if err != nil {
+				return records, err
+			}
+			recordsBytes = append(recordsBytes, fileBytes)
+		}
+
+	case v1alpha1.BackupTypeConfigMap, v1alpha1.BackupTypeSecret:
+		gvr := configMapGVR
+		if config.Type == v1alpha1.BackupTypeSecret {
+			gvr = secretGVR
+		}
+
+		holderList, err := client.Resource(gvr).Namespace(config.Namespace).List(globals.ExecContext.Context,
+			v1.ListOptions{LabelSelector: BackupLabel + "=true"})
+		if err != nil {
+			return records, err
+		}
+
+		for _, holder := range holderList.Items {
+			recordBytes, err := getHolderRecord(holder, config.Type)
+			if err != nil {
+				return records, fmt.Errorf("error reading backup '%s': %s", holder.GetName(), err.Error())
+			}
+			recordsBytes = append(recordsBytes, recordBytes)
+		}
+
+	default:
+		return records, fmt.Errorf("backup type must be '%s', '%s' or '%s'",
+			v1alpha1.BackupTypeDirectory, v1alpha1.BackupTypeConfigMap, v1alpha1.BackupTypeSecret)
+	}
+
+	for _, recordBytes := range recordsBytes {
+		record := RecordT{}
+		err = yaml.Unmarshal(recordBytes, &record)
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp < records[j].Timestamp
+	})
+
+	return records, nil
+}
+
+// getHolderRecord return the record stored inside a ConfigMap or a Secret
+func getHolderRecord(holder unstructured.Unstructured, backupType string) (recordBytes []byte, err error) {
+
+	if backupType == v1alpha1.BackupTypeConfigMap {
+		record, found, err := unstructured.NestedString(holder.Object, "data", recordKey)
+		if err != nil || !found {
+			return recordBytes, fmt.Errorf("key '%s' not found", recordKey)
+		}
+		return []byte(record), nil
+	}
+
+	// Secrets' data is base64 encoded. The typed conversion decodes it
+	secret := struct {
+		Data map[string][]byte `json:"data"`
+	}{}
+
+	holderBytes, err := holder.MarshalJSON()
+	if err != nil {
+		return recordBytes, err
+	}
+
+	err = yaml.Unmarshal(holderBytes, &secret)
+	if err != nil {
+		return recordBytes, err
+	}
+
+	recordBytes, found := secret.Data[recordKey]
+	if !found {
+		return recordBytes, fmt.Errorf("key '%s' not found", recordKey)
+	}
+
+	return recordBytes, nil
+}
+
+// truncate cuts a string to a maximum length
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return strings.TrimRight(value[:length], "-.")
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"strings"
+	"testing"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+// newTestRecord return the backup of an object of the given kind, taken always at the same instant
+func newTestRecord(apiVersion, kind string) RecordT {
+	object := unstructured.Unstructured{Object: map[string]interface{}{}}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace("default")
+	object.SetName("object")
+
+	return RecordT{Timestamp: "2026-01-01T00:00:00Z", Cluster: "default", Rule: "rule", Object: object.Object}
+}
+
+func TestSaveDirectory(t *testing.T) {
+	config := v1alpha1.BackupT{Type: v1alpha1.BackupTypeDirectory, Path: t.TempDir()}
+
+	// Records of the same object taken in the same second must not overwrite each other
+	for range 3 {
+		err := Save(nil, config, newTestRecord("v1", "ConfigMap"))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	records, err := List(nil, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+}
+
+func TestSaveSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		backupType string
+		wantError  string
+	}{
+		{"secret into directory", "v1", v1alpha1.BackupTypeDirectory, "secrets can only be backed up into 'secret' destinations"},
+		{"secret into configMap", "v1", v1alpha1.BackupTypeConfigMap, "secrets can only be backed up into 'secret' destinations"},
+		{"secret of other group into directory", "example.com/v1", v1alpha1.BackupTypeDirectory, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := v1alpha1.BackupT{Type: test.backupType, Path: t.TempDir(), Namespace: "hitman"}
+
+			err := Save(nil, config, newTestRecord(test.apiVersion, "Secret"))
+			if test.wantError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if test.wantError != "" && (err == nil || !strings.Contains(err.Error(), test.wantError)) {
+				t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+			}
+		})
+	}
+}
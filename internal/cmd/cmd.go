@@ -8,7 +8,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"hitman/internal/cmd/crds"
+	"hitman/internal/cmd/rbac"
+	"hitman/internal/cmd/restore"
+	"hitman/internal/cmd/rules"
 	"hitman/internal/cmd/run"
+	"hitman/internal/cmd/schema"
 	"hitman/internal/cmd/version"
 )
 
@@ -30,6 +35,11 @@ func NewRootCommand(name string) *cobra.Command {
 	c.AddCommand(
 		version.NewCommand(),
 		run.NewCommand(),
+		crds.NewCommand(),
+		rbac.NewCommand(),
+		restore.NewCommand(),
+		rules.NewCommand(),
+		schema.NewCommand(),
 	)
 
 	return c
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package crds
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	//
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	//
+	"hitman/internal/schema"
+)
+
+const (
+	descriptionShort = `Print the CustomResourceDefinitions served by hitman`
+	descriptionLong  = `
+	Crds print the CustomResourceDefinitions for Hitman and HitmanPolicy kinds.
+	They are generated from hitman API types, so they can be applied with kubectl:
+	hitman crds | kubectl apply -f -`
+
+	//
+	CrdNotConvertedErrorMessage  = "impossible to convert CustomResourceDefinition: %s"
+	CrdNotMarshalledErrorMessage = "impossible to marshal CustomResourceDefinition: %s"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "crds",
+		DisableFlagsInUseLine: true,
+		Short:                 descriptionShort,
+		Long:                  strings.ReplaceAll(descriptionLong, "\t", ""),
+
+		Run: RunCommand,
+	}
+
+	return cmd
+}
+
+func RunCommand(cmd *cobra.Command, args []string) {
+	for _, crd := range schema.GetCustomResourceDefinitions() {
+
+		crdObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&crd)
+		if err != nil {
+			log.Fatalf(CrdNotConvertedErrorMessage, err)
+		}
+
+		// Fields filled by Kubernetes are not needed in a manifest
+		delete(crdObject, "status")
+		delete(crdObject["metadata"].(map[string]interface{}), "creationTimestamp")
+
+		crdBytes, err := yaml.Marshal(crdObject)
+		if err != nil {
+			log.Fatalf(CrdNotMarshalledErrorMessage, err)
+		}
+
+		fmt.Printf("---\n%s", crdBytes)
+	}
+}
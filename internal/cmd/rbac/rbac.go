@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	//
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	//
+	"hitman/internal/config"
+	"hitman/internal/globals"
+	"hitman/internal/rbac"
+)
+
+const (
+	descriptionShort = `Print the minimal RBAC needed to process a config`
+	descriptionLong  = `
+	Rbac reads a config file and print the minimal ClusterRole needed by hitman to process it.
+	When --namespaced is set, permissions confined to a namespace are printed as Roles instead.
+	Permissions that can not be known without a cluster are reported as warnings.
+	When --cluster is set, only the permissions needed in that cluster are printed`
+
+	//
+	ConfigFlagErrorMessage        = "impossible to get flag --config: %s"
+	NameFlagErrorMessage          = "impossible to get flag --name: %s"
+	NamespacedFlagErrorMessage    = "impossible to get flag --namespaced: %s"
+	ControllerFlagErrorMessage    = "impossible to get flag --controller: %s"
+	ClusterFlagErrorMessage       = "impossible to get flag --cluster: %s"
+	ConfigNotParsedErrorMessage   = "impossible to parse config file: %s"
+	RoleNotMarshalledErrorMessage = "impossible to marshal role: %s"
+
+	// Allowlists of the expressions expanded in config files
+	ConfigEnvAllowlistFlagErrorMessage  = "impossible to get flag --config-env-allowlist: %s"
+	ConfigFileAllowlistFlagErrorMessage = "impossible to get flag --config-file-allowlist: %s"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "rbac",
+		DisableFlagsInUseLine: true,
+		Short:                 descriptionShort,
+		Long:                  strings.ReplaceAll(descriptionLong, "\t", ""),
+
+		Run: RunCommand,
+	}
+
+	//
+	cmd.Flags().StringSlice("config", []string{"hitman.yaml"}, "Paths to the YAML config files. Globs and directories are accepted too")
+	cmd.Flags().StringSlice("config-env-allowlist", config.DefaultConfigEnvAllowlist, "Environment variables that can be expanded in config files as ${NAME}. Globs are accepted")
+	cmd.Flags().StringSlice("config-file-allowlist", []string{}, "Files that can be expanded in config files as ${file:path}. Globs are accepted")
+	cmd.Flags().String("name", "hitman", "Name for the generated ClusterRole and Roles")
+	cmd.Flags().Bool("namespaced", false, "Print permissions confined to a namespace as Roles")
+	cmd.Flags().Bool("controller", false, "Include permissions to read config from Hitman and HitmanPolicy objects")
+	cmd.Flags().String("cluster", "", "Only print permissions needed in given cluster (Default: all of them)")
+
+	return cmd
+}
+
+func RunCommand(cmd *cobra.Command, args []string) {
+
+	configPaths, err := cmd.Flags().GetStringSlice("config")
+	if err != nil {
+		log.Fatalf(ConfigFlagErrorMessage, err)
+	}
+
+	configEnvAllowlistFlag, err := cmd.Flags().GetStringSlice("config-env-allowlist")
+	if err != nil {
+		log.Fatalf(ConfigEnvAllowlistFlagErrorMessage, err)
+	}
+	globals.ExecContext.ConfigEnvAllowlist = configEnvAllowlistFlag
+
+	configFileAllowlistFlag, err := cmd.Flags().GetStringSlice("config-file-allowlist")
+	if err != nil {
+		log.Fatalf(ConfigFileAllowlistFlagErrorMessage, err)
+	}
+	globals.ExecContext.ConfigFileAllowlist = configFileAllowlistFlag
+
+	nameFlag, err := cmd.Flags().GetString("name")
+	if err != nil {
+		log.Fatalf(NameFlagErrorMessage, err)
+	}
+
+	namespacedFlag, err := cmd.Flags().GetBool("namespaced")
+	if err != nil {
+		log.Fatalf(NamespacedFlagErrorMessage, err)
+	}
+
+	controllerFlag, err := cmd.Flags().GetBool("controller")
+	if err != nil {
+		log.Fatalf(ControllerFlagErrorMessage, err)
+	}
+
+	clusterFlag, err := cmd.Flags().GetString("cluster")
+	if err != nil {
+		log.Fatalf(ClusterFlagErrorMessage, err)
+	}
+
+	configContent, err := config.ReadFiles(configPaths)
+	if err != nil {
+		log.Fatalf(ConfigNotParsedErrorMessage, err)
+	}
+
+	permissions, warnings := rbac.GetPermissions(configContent, clusterFlag)
+
+	// Following permissions are needed in the cluster where Hitman runs
+	if clusterFlag == "" {
+		permissions = append(permissions, rbac.GetClustersPermissions(configContent)...)
+	}
+
+	if controllerFlag && clusterFlag == "" {
+		permissions = append(permissions, rbac.GetControllerPermissions()...)
+	}
+
+	// Warnings are not part of the manifests, so they can be piped to kubectl
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	// Group the permissions by namespace. Empty one means cluster-wide
+	permissionsByNamespace := map[string][]rbac.PermissionT{}
+	for _, permission := range permissions {
+		namespace := permission.Namespace
+		if !namespacedFlag {
+			namespace = ""
+		}
+		permissionsByNamespace[namespace] = append(permissionsByNamespace[namespace], permission)
+	}
+
+	namespaces := make([]string, 0, len(permissionsByNamespace))
+	for namespace := range permissionsByNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		var role interface{}
+
+		rules := rbac.GetPolicyRules(permissionsByNamespace[namespace])
+
+		switch namespace {
+		case "":
+			role = &rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: nameFlag},
+				Rules:      rules,
+			}
+		default:
+			role = &rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+				ObjectMeta: metav1.ObjectMeta{Name: nameFlag, Namespace: namespace},
+				Rules:      rules,
+			}
+		}
+
+		roleObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(role)
+		if err != nil {
+			log.Fatalf(RoleNotMarshalledErrorMessage, err)
+		}
+
+		// Fields filled by Kubernetes are not needed in a manifest
+		delete(roleObject["metadata"].(map[string]interface{}), "creationTimestamp")
+
+		roleBytes, err := yaml.Marshal(roleObject)
+		if err != nil {
+			log.Fatalf(RoleNotMarshalledErrorMessage, err)
+		}
+
+		fmt.Printf("---\n%s", roleBytes)
+	}
+}
@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package restore
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	//
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/backup"
+	"hitman/internal/globals"
+	"hitman/internal/kubernetes"
+)
+
+const (
+	descriptionShort = `Re-create objects from the backups taken before their deletion`
+	descriptionLong  = `
+	Restore reads the backups taken by hitman before deleting objects, and creates them again.
+	Backups can be filtered by rule, cluster, namespace, name and time range.
+	Objects that already exist are never overwritten`
+
+	//
+	BackupTypeFlagErrorMessage      = "impossible to get flag --backup-type: %s"
+	PathFlagErrorMessage            = "impossible to get flag --path: %s"
+	BackupNamespaceFlagErrorMessage = "impossible to get flag --backup-namespace: %s"
+	RuleFlagErrorMessage            = "impossible to get flag --rule: %s"
+	ClusterFlagErrorMessage         = "impossible to get flag --cluster: %s"
+	NamespaceFlagErrorMessage       = "impossible to get flag --namespace: %s"
+	NameFlagErrorMessage            = "impossible to get flag --name: %s"
+	SinceFlagErrorMessage           = "impossible to get flag --since: %s"
+	UntilFlagErrorMessage           = "impossible to get flag --until: %s"
+	TimeFlagNotParsedErrorMessage   = "impossible to parse time as RFC3339: %s"
+	DryRunFlagErrorMessage          = "impossible to get flag --dry-run: %s"
+	KubeconfigFlagErrorMessage      = "impossible to get flag --kubeconfig: %s"
+	ContextFlagErrorMessage         = "impossible to get flag --context: %s"
+	KubernetesClientErrorMessage    = "impossible to create Kubernetes client: %s"
+	BackupsNotListedErrorMessage    = "impossible to list backups: %s"
+)
+
+// filterT defines the conditions a backup must meet to be restored. Empty fields match everything
+type filterT struct {
+	rule      string
+	cluster   string
+	namespace string
+	name      string
+	since     time.Time
+	until     time.Time
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "restore",
+		DisableFlagsInUseLine: true,
+		Short:                 descriptionShort,
+		Long:                  strings.ReplaceAll(descriptionLong, "\t", ""),
+
+		Run: RunCommand,
+	}
+
+	//
+	cmd.Flags().String("backup-type", v1alpha1.BackupTypeDirectory, "Where backups are stored: directory, configMap or secret")
+	cmd.Flags().String("path", "", "Directory where backups are stored")
+	cmd.Flags().String("backup-namespace", "", "Namespace where backup ConfigMaps or Secrets are stored")
+	cmd.Flags().String("rule", "", "Only restore objects killed by given rule")
+	cmd.Flags().String("cluster", "", "Only restore objects killed in given cluster")
+	cmd.Flags().String("namespace", "", "Only restore objects of given namespace")
+	cmd.Flags().String("name", "", "Only restore objects with given name")
+	cmd.Flags().String("since", "", "Only restore objects killed after given RFC3339 time")
+	cmd.Flags().String("until", "", "Only restore objects killed before given RFC3339 time")
+	cmd.Flags().Bool("dry-run", false, "Print the objects to restore without creating them")
+	cmd.Flags().String("kubeconfig", "", "Path to the kubeconfig file of the cluster where objects are restored (Default: discovered)")
+	cmd.Flags().String("context", "", "Context of the kubeconfig to use (Default: current one)")
+
+	return cmd
+}
+
+func RunCommand(cmd *cobra.Command, args []string) {
+
+	backupConfig := v1alpha1.BackupT{}
+	filter := filterT{}
+	clientSettings := v1alpha1.KubernetesClientT{}
+
+	var err error
+
+	backupConfig.Type, err = cmd.Flags().GetString("backup-type")
+	if err != nil {
+		log.Fatalf(BackupTypeFlagErrorMessage, err)
+	}
+
+	backupConfig.Path, err = cmd.Flags().GetString("path")
+	if err != nil {
+		log.Fatalf(PathFlagErrorMessage, err)
+	}
+
+	backupConfig.Namespace, err = cmd.Flags().GetString("backup-namespace")
+	if err != nil {
+		log.Fatalf(BackupNamespaceFlagErrorMessage, err)
+	}
+
+	filter.rule, err = cmd.Flags().GetString("rule")
+	if err != nil {
+		log.Fatalf(RuleFlagErrorMessage, err)
+	}
+
+	filter.cluster, err = cmd.Flags().GetString("cluster")
+	if err != nil {
+		log.Fatalf(ClusterFlagErrorMessage, err)
+	}
+
+	filter.namespace, err = cmd.Flags().GetString("namespace")
+	if err != nil {
+		log.Fatalf(NamespaceFlagErrorMessage, err)
+	}
+
+	filter.name, err = cmd.Flags().GetString("name")
+	if err != nil {
+		log.Fatalf(NameFlagErrorMessage, err)
+	}
+
+	sinceFlag, err := cmd.Flags().GetString("since")
+	if err != nil {
+		log.Fatalf(SinceFlagErrorMessage, err)
+	}
+
+	untilFlag, err := cmd.Flags().GetString("until")
+	if err != nil {
+		log.Fatalf(UntilFlagErrorMessage, err)
+	}
+
+	if sinceFlag != "" {
+		filter.since, err = time.Parse(time.RFC3339, sinceFlag)
+		if err != nil {
+			log.Fatalf(TimeFlagNotParsedErrorMessage, err)
+		}
+	}
+
+	if untilFlag != "" {
+		filter.until, err = time.Parse(time.RFC3339, untilFlag)
+		if err != nil {
+			log.Fatalf(TimeFlagNotParsedErrorMessage, err)
+		}
+	}
+
+	dryRunFlag, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		log.Fatalf(DryRunFlagErrorMessage, err)
+	}
+
+	clientSettings.Kubeconfig, err = cmd.Flags().GetString("kubeconfig")
+	if err != nil {
+		log.Fatalf(KubeconfigFlagErrorMessage, err)
+	}
+
+	clientSettings.Context, err = cmd.Flags().GetString("context")
+	if err != nil {
+		log.Fatalf(ContextFlagErrorMessage, err)
+	}
+
+	/////////////////////////////
+	// EXECUTION FLOW RELATED
+	/////////////////////////////
+
+	restConfig, err := kubernetes.GetConfig(clientSettings)
+	if err != nil {
+		log.Fatalf(KubernetesClientErrorMessage, err)
+	}
+
+	client, err := kubernetes.NewClient(restConfig)
+	if err != nil {
+		log.Fatalf(KubernetesClientErrorMessage, err)
+	}
+
+	restMapper, err := kubernetes.NewRESTMapper(restConfig)
+	if err != nil {
+		log.Fatalf(KubernetesClientErrorMessage, err)
+	}
+
+	records, err := backup.List(client, backupConfig)
+	if err != nil {
+		log.Fatalf(BackupsNotListedErrorMessage, err)
+	}
+
+	restoredObjects := 0
+	for _, record := range records {
+		if !matchesFilter(record, filter) {
+			continue
+		}
+
+		object := &unstructured.Unstructured{Object: record.Object}
+		objectReference := strings.TrimPrefix(fmt.Sprintf("%s %s/%s", object.GetKind(), object.GetNamespace(), object.GetName()), "/")
+
+		if dryRunFlag {
+			restoredObjects++
+			fmt.Printf("%s would be restored (killed by '%s' at %s)\n", objectReference, record.Rule, record.Timestamp)
+			continue
+		}
+
+		err = restoreObject(client, restMapper, object)
+		if errors.IsAlreadyExists(err) {
+			fmt.Printf("%s already exists. Skipping\n", objectReference)
+			continue
+		}
+
+		if err != nil {
+			fmt.Printf("%s could not be restored: %s\n", objectReference, err.Error())
+			continue
+		}
+
+		restoredObjects++
+		fmt.Printf("%s restored (killed by '%s' at %s)\n", objectReference, record.Rule, record.Timestamp)
+	}
+
+	if dryRunFlag {
+		fmt.Printf("%d objects would be restored\n", restoredObjects)
+		return
+	}
+
+	fmt.Printf("%d objects restored\n", restoredObjects)
+}
+
+// matchesFilter return true when a backup meets all the conditions of the filter
+func matchesFilter(record backup.RecordT, filter filterT) bool {
+	object := unstructured.Unstructured{Object: record.Object}
+
+	if (filter.rule != "" && record.Rule != filter.rule) ||
+		(filter.cluster != "" && record.Cluster != filter.cluster) ||
+		(filter.namespace != "" && object.GetNamespace() != filter.namespace) ||
+		(filter.name != "" && object.GetName() != filter.name) {
+		return false
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, record.Timestamp)
+	if err != nil {
+		return false
+	}
+
+	if (!filter.since.IsZero() && timestamp.Before(filter.since)) ||
+		(!filter.until.IsZero() && timestamp.After(filter.until)) {
+		return false
+	}
+
+	return true
+}
+
+// restoreObject creates an object again. Its resource is discovered from its kind
+func restoreObject(client *dynamic.DynamicClient, restMapper *restmapper.DeferredDiscoveryRESTMapper, object *unstructured.Unstructured) (err error) {
+
+	gvk := object.GroupVersionKind()
+
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+
+	var resourceInterface dynamic.ResourceInterface = client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resourceInterface = client.Resource(mapping.Resource).Namespace(object.GetNamespace())
+	}
+
+	_, err = resourceInterface.Create(globals.ExecContext.Context, object, v1.CreateOptions{})
+	return err
+}
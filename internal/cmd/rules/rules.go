@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	//
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+
+	//
+	"hitman/internal/config"
+	"hitman/internal/globals"
+	"hitman/internal/rules"
+)
+
+const (
+	descriptionShort = `List the rules of a config`
+	descriptionLong  = `
+	Rules reads a config file and print its rules with their names, state and metadata.
+	When --selector is set, only the rules whose labels match it are printed`
+
+	//
+	ConfigFlagErrorMessage      = "impossible to get flag --config: %s"
+	SelectorFlagErrorMessage    = "impossible to get flag --selector: %s"
+	ConfigNotParsedErrorMessage = "impossible to parse config file: %s"
+	SelectorNotParsedMessage    = "impossible to parse selector: %s"
+
+	// Allowlists of the expressions expanded in config files
+	ConfigEnvAllowlistFlagErrorMessage  = "impossible to get flag --config-env-allowlist: %s"
+	ConfigFileAllowlistFlagErrorMessage = "impossible to get flag --config-file-allowlist: %s"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "rules",
+		DisableFlagsInUseLine: true,
+		Short:                 descriptionShort,
+		Long:                  strings.ReplaceAll(descriptionLong, "\t", ""),
+
+		Run: RunCommand,
+	}
+
+	//
+	cmd.Flags().StringSlice("config", []string{"hitman.yaml"}, "Paths to the YAML config files. Globs and directories are accepted too")
+	cmd.Flags().StringSlice("config-env-allowlist", config.DefaultConfigEnvAllowlist, "Environment variables that can be expanded in config files as ${NAME}. Globs are accepted")
+	cmd.Flags().StringSlice("config-file-allowlist", []string{}, "Files that can be expanded in config files as ${file:path}. Globs are accepted")
+	cmd.Flags().StringP("selector", "l", "", "Only print rules whose labels match given selector (e.g. team=platform)")
+
+	return cmd
+}
+
+func RunCommand(cmd *cobra.Command, args []string) {
+
+	configPaths, err := cmd.Flags().GetStringSlice("config")
+	if err != nil {
+		log.Fatalf(ConfigFlagErrorMessage, err)
+	}
+
+	configEnvAllowlistFlag, err := cmd.Flags().GetStringSlice("config-env-allowlist")
+	if err != nil {
+		log.Fatalf(ConfigEnvAllowlistFlagErrorMessage, err)
+	}
+	globals.ExecContext.ConfigEnvAllowlist = configEnvAllowlistFlag
+
+	configFileAllowlistFlag, err := cmd.Flags().GetStringSlice("config-file-allowlist")
+	if err != nil {
+		log.Fatalf(ConfigFileAllowlistFlagErrorMessage, err)
+	}
+	globals.ExecContext.ConfigFileAllowlist = configFileAllowlistFlag
+
+	selectorFlag, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		log.Fatalf(SelectorFlagErrorMessage, err)
+	}
+
+	selector, err := labels.Parse(selectorFlag)
+	if err != nil {
+		log.Fatalf(SelectorNotParsedMessage, err)
+	}
+
+	configContent, err := config.ReadFiles(configPaths)
+	if err != nil {
+		log.Fatalf(ConfigNotParsedErrorMessage, err)
+	}
+
+	// Config is validated the same way it is on 'run', so wrong names or schedules are caught here too
+	err = config.ApplyDefaults(configContent)
+	if err != nil {
+		log.Fatalf(ConfigNotParsedErrorMessage, err)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tENABLED\tDRY-RUN\tTARGET\tSCHEDULE\tCLUSTERS\tLABELS\tDESCRIPTION")
+
+	for resourceIndex, resource := range configContent.Spec.Resources {
+		if !selector.Matches(labels.Set(resource.Labels)) {
+			continue
+		}
+
+		schedule := resource.Schedule
+		if schedule == "" {
+			schedule = configContent.Spec.Synchronization.Time
+		}
+
+		clusters := strings.Join(resource.Clusters, ",")
+		if clusters == "" {
+			clusters = "*"
+		}
+
+		var ruleLabels []string
+		for labelName, labelValue := range resource.Labels {
+			ruleLabels = append(ruleLabels, labelName+"="+labelValue)
+		}
+		sort.Strings(ruleLabels)
+
+		fmt.Fprintf(writer, "%s\t%t\t%t\t%s\t%s\t%s\t%s\t%s\n",
+			rules.GetName(resourceIndex, resource),
+			rules.IsEnabled(resource),
+			rules.IsDryRun(resource),
+			strings.TrimPrefix(resource.Target.Group+"/"+resource.Target.Version+"/"+resource.Target.Resource, "/"),
+			schedule,
+			clusters,
+			strings.Join(ruleLabels, ","),
+			resource.Description,
+		)
+	}
+
+	writer.Flush()
+}
@@ -5,18 +5,26 @@ package run
 
 import (
 	"fmt"
-	"hitman/api/v1alpha1"
 	"log"
-	"reflect"
 	"time"
 
 	//
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
 
 	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/audit"
 	"hitman/internal/config"
+	"hitman/internal/controller"
 	"hitman/internal/globals"
+	"hitman/internal/kubernetes"
+	"hitman/internal/metrics"
+	"hitman/internal/notifications"
 	"hitman/internal/processor"
+	"hitman/internal/rbac"
+	"hitman/internal/scheduler"
+	"hitman/internal/template"
 )
 
 const (
@@ -28,12 +36,32 @@ const (
 	//
 
 	//
-	ConfigFlagErrorMessage          = "impossible to get flag --config: %s"
-	ConfigNotParsedErrorMessage     = "impossible to parse config file: %s"
-	LogLevelFlagErrorMessage        = "impossible to get flag --log-level: %s"
-	DisableTraceFlagErrorMessage    = "impossible to get flag --disable-trace: %s"
-	DryRunFlagErrorMessage          = "impossible to get flag --dry-run: %s"
-	UnableParseDurationErrorMessage = "unable to parse duration: %s"
+	ConfigFlagErrorMessage       = "impossible to get flag --config: %s"
+	ConfigSourceFlagErrorMessage = "impossible to get flag --config-source: %s"
+	ControllerErrorMessage       = "impossible to watch config objects in Kubernetes: %s"
+	ConfigNotParsedErrorMessage  = "impossible to parse config file: %s"
+	LogLevelFlagErrorMessage     = "impossible to get flag --log-level: %s"
+	LogFormatFlagErrorMessage    = "impossible to get flag --log-format: %s"
+	DisableTraceFlagErrorMessage = "impossible to get flag --disable-trace: %s"
+	DryRunFlagErrorMessage       = "impossible to get flag --dry-run: %s"
+	NowFlagErrorMessage          = "impossible to get flag --now: %s"
+	NowFlagNotParsedErrorMessage = "impossible to parse flag --now as RFC3339 time: %s"
+	MetricsFlagErrorMessage      = "impossible to get flag --metrics-bind-address: %s"
+	KubeconfigFlagErrorMessage   = "impossible to get flag --kubeconfig: %s"
+	ContextFlagErrorMessage      = "impossible to get flag --context: %s"
+	KubeAPIQPSFlagErrorMessage   = "impossible to get flag --kube-api-qps: %s"
+	KubeAPIBurstFlagErrorMessage = "impossible to get flag --kube-api-burst: %s"
+	KubeAPITimeoutErrorMessage   = "impossible to get flag --kube-api-timeout: %s"
+	UserAgentFlagErrorMessage    = "impossible to get flag --user-agent: %s"
+
+	// Allowlists of the expressions expanded in config files
+	ConfigEnvAllowlistFlagErrorMessage  = "impossible to get flag --config-env-allowlist: %s"
+	ConfigFileAllowlistFlagErrorMessage = "impossible to get flag --config-file-allowlist: %s"
+)
+
+const (
+	ConfigSourceFile       = "file"
+	ConfigSourceKubernetes = "kubernetes"
 )
 
 func NewCommand() *cobra.Command {
@@ -48,9 +76,21 @@ func NewCommand() *cobra.Command {
 
 	//
 	cmd.Flags().String("log-level", "info", "Verbosity level for logs")
+	cmd.Flags().String("log-format", "json", "Format for logs: json or console")
 	cmd.Flags().Bool("disable-trace", true, "Disable showing traces in logs")
-	cmd.Flags().String("config", "hitman.yaml", "Path to the YAML config file")
+	cmd.Flags().StringSlice("config", []string{"hitman.yaml"}, "Paths to the YAML config files. Globs and directories are accepted too")
+	cmd.Flags().StringSlice("config-env-allowlist", config.DefaultConfigEnvAllowlist, "Environment variables that can be expanded in config files as ${NAME}. Globs are accepted")
+	cmd.Flags().StringSlice("config-file-allowlist", []string{}, "Files that can be expanded in config files as ${file:path}. Globs are accepted")
+	cmd.Flags().String("config-source", ConfigSourceFile, "Where config is read from: file or kubernetes (Hitman and HitmanPolicy objects)")
 	cmd.Flags().Bool("dry-run", false, "Disable performing actual actions")
+	cmd.Flags().String("now", "", "Freeze the clock used by templates at given RFC3339 time (useful with --dry-run)")
+	cmd.Flags().String("metrics-bind-address", ":8080", "Address where Prometheus metrics are served. Empty disables them")
+	cmd.Flags().String("kubeconfig", "", "Path to the kubeconfig file of the cluster where Hitman runs (Default: discovered)")
+	cmd.Flags().String("context", "", "Context of the kubeconfig to use (Default: current one)")
+	cmd.Flags().Float32("kube-api-qps", 0, "Maximum queries per second to Kubernetes API (Default: 20)")
+	cmd.Flags().Int("kube-api-burst", 0, "Maximum burst of queries to Kubernetes API (Default: 30)")
+	cmd.Flags().Duration("kube-api-timeout", 0, "Timeout for requests to Kubernetes API. Zero means no timeout")
+	cmd.Flags().String("user-agent", "", "User agent sent to Kubernetes API (Default: client-go one)")
 
 	return cmd
 }
@@ -59,11 +99,33 @@ func NewCommand() *cobra.Command {
 // Ref: https://pkg.go.dev/github.com/spf13/pflag#StringSlice
 func RunCommand(cmd *cobra.Command, args []string) {
 
-	configPath, err := cmd.Flags().GetString("config")
+	configPaths, err := cmd.Flags().GetStringSlice("config")
 	if err != nil {
 		log.Fatalf(ConfigFlagErrorMessage, err)
 	}
 
+	configEnvAllowlistFlag, err := cmd.Flags().GetStringSlice("config-env-allowlist")
+	if err != nil {
+		log.Fatalf(ConfigEnvAllowlistFlagErrorMessage, err)
+	}
+	globals.ExecContext.ConfigEnvAllowlist = configEnvAllowlistFlag
+
+	configFileAllowlistFlag, err := cmd.Flags().GetStringSlice("config-file-allowlist")
+	if err != nil {
+		log.Fatalf(ConfigFileAllowlistFlagErrorMessage, err)
+	}
+	globals.ExecContext.ConfigFileAllowlist = configFileAllowlistFlag
+
+	configSourceFlag, err := cmd.Flags().GetString("config-source")
+	if err != nil {
+		log.Fatalf(ConfigSourceFlagErrorMessage, err)
+	}
+
+	if configSourceFlag != ConfigSourceFile && configSourceFlag != ConfigSourceKubernetes {
+		log.Fatalf(ConfigSourceFlagErrorMessage,
+			fmt.Sprintf("value must be '%s' or '%s'", ConfigSourceFile, ConfigSourceKubernetes))
+	}
+
 	// Init the logger and store the level into the context
 	logLevelFlag, err := cmd.Flags().GetString("log-level")
 	if err != nil {
@@ -76,7 +138,12 @@ func RunCommand(cmd *cobra.Command, args []string) {
 		log.Fatalf(DisableTraceFlagErrorMessage, err)
 	}
 
-	err = globals.SetLogger(logLevelFlag, disableTraceFlag)
+	logFormatFlag, err := cmd.Flags().GetString("log-format")
+	if err != nil {
+		log.Fatalf(LogFormatFlagErrorMessage, err)
+	}
+
+	err = globals.SetLogger(logLevelFlag, logFormatFlag, disableTraceFlag)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -87,6 +154,31 @@ func RunCommand(cmd *cobra.Command, args []string) {
 	}
 	globals.ExecContext.DryRun = dryRunFlag
 
+	// Freeze the clock used by templates when requested. This makes dry-runs reproducible
+	nowFlag, err := cmd.Flags().GetString("now")
+	if err != nil {
+		log.Fatalf(NowFlagErrorMessage, err)
+	}
+
+	if nowFlag != "" {
+		frozenNow, err := time.Parse(time.RFC3339, nowFlag)
+		if err != nil {
+			log.Fatalf(NowFlagNotParsedErrorMessage, err)
+		}
+		template.SetFixedClock(frozenNow)
+	}
+
+	metricsBindAddressFlag, err := cmd.Flags().GetString("metrics-bind-address")
+	if err != nil {
+		log.Fatalf(MetricsFlagErrorMessage, err)
+	}
+
+	// Kubernetes client settings. They take precedence over the ones in the config
+	err = setKubernetesClientFlags(cmd)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	/////////////////////////////
 	// EXECUTION FLOW RELATED
 	/////////////////////////////
@@ -95,85 +187,218 @@ func RunCommand(cmd *cobra.Command, args []string) {
 
 	// Parse and store the config in the background
 	// Main process must wait until config is being processed, at least, once
-	configReady := make(chan struct{})
-	go configProcessorWorker(configPath, configReady)
-	<-configReady // Wait until config is ready
+	var controllerObj *controller.Controller
 
-	//
-	processorObj, err := processor.NewProcessor()
-	if err != nil {
-		globals.ExecContext.Logger.Infof("error creating processor: %s", err.Error())
+	switch configSourceFlag {
+	case ConfigSourceKubernetes:
+		controllerObj, err = controller.NewController()
+		if err != nil {
+			globals.ExecContext.Logger.Fatalf(ControllerErrorMessage, err)
+		}
+
+		err = controllerObj.Start() // Wait until config is ready
+		if err != nil {
+			globals.ExecContext.Logger.Fatalf(ControllerErrorMessage, err)
+		}
+
+	default:
+		configReady := make(chan struct{})
+		go configProcessorWorker(configPaths, configReady)
+		<-configReady // Wait until config is ready
+	}
+
+	if metricsBindAddressFlag != "" {
+		go metrics.Serve(metricsBindAddressFlag)
 	}
 
-	for {
-		globals.ExecContext.Logger.Info("syncing resources")
+	// One processor is kept per cluster. They are created on demand as clusters can change on config reloads
+	clusterProcessors := processor.NewClusterProcessors()
+
+	configSnapshot := globals.ExecContext.Config.Load()
+	processors := clusterProcessors.Get(processor.GetClusters(configSnapshot), configSnapshot.Spec.Kubernetes)
+
+	// Missing permissions make rules fail on every loop. Warn about them early
+	checkPermissions(processors, controllerObj != nil)
+
+	// Rules run on their own schedules, so the scheduler is asked for due rules on every tick
+	schedulerObj := scheduler.NewScheduler()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
 
-		globals.ExecContext.Config.Mutex.RLock()
-		err = processorObj.SyncResources()
+	for ; true; <-ticker.C {
+		// The whole loop works over the same config. Reloads take effect on next one, and they are never blocked
+		configSnapshot = globals.ExecContext.Config.Load()
+
+		dueResourceIndexes := schedulerObj.GetDueResources(configSnapshot, time.Now())
+		if len(dueResourceIndexes) == 0 {
+			continue
+		}
+
+		globals.ExecContext.Logger.Infow("syncing resources", "rules", len(dueResourceIndexes))
+
+		// Audit sinks and notification receivers are only rebuilt when their settings change
+		audit.Configure(configSnapshot.Spec.Audit)
+		notifications.Configure(configSnapshot.Spec.Notifications)
+
+		// Templates are validated with the config, so this can only fail on unexpected situations
+		err = template.SetSnippets(configSnapshot.Spec.Templates)
 		if err != nil {
-			globals.ExecContext.Logger.Infof("error syncing resources: %s", err)
+			globals.ExecContext.Logger.Infow("error parsing templates", "error", err.Error())
+		}
+
+		processors = clusterProcessors.Get(processor.GetClusters(configSnapshot), configSnapshot.Spec.Kubernetes)
+
+		var clusterRuleStatuses []map[string]*v1alpha1.RuleStatusT
+		for _, processorObj := range processors {
+			err = processorObj.SyncResources(configSnapshot, dueResourceIndexes)
+			if err != nil {
+				globals.ExecContext.Logger.Infow("error syncing resources",
+					"cluster", processorObj.Cluster.Name, "error", err.Error())
+			}
+
+			clusterRuleStatuses = append(clusterRuleStatuses, processorObj.LastRunStatuses)
+		}
+
+		// Actions performed during the loop are notified in a single batch
+		notifications.Flush()
+
+		// Report the results into the objects the rules were read from
+		if controllerObj != nil {
+			controllerObj.ReportStatus(configSnapshot.Spec.Resources, clusterRuleStatuses...)
 		}
 
 		//
-		globals.ExecContext.Logger.Infof("syncing again in %s",
-			globals.ExecContext.Config.Spec.Synchronization.CarriedTime.String())
-		globals.ExecContext.Config.Mutex.RUnlock()
-		time.Sleep(globals.ExecContext.Config.Spec.Synchronization.CarriedTime)
+		globals.ExecContext.Logger.Infof("syncing again at %s",
+			schedulerObj.GetNextRun().Format(time.RFC3339))
 	}
 }
 
-// configProcessorWorker TODO - Reads and applies configuration initially,
-// then reloads periodically
-func configProcessorWorker(configPath string, configReady chan<- struct{}) {
-	// Initial load
-	applyConfig(configPath)
+// setKubernetesClientFlags stores the Kubernetes client settings defined by flags into the context
+func setKubernetesClientFlags(cmd *cobra.Command) (err error) {
+	flags := &globals.ExecContext.KubernetesClientFlags
 
-	// Signal main that initial config is ready
-	close(configReady)
+	flags.Kubeconfig, err = cmd.Flags().GetString("kubeconfig")
+	if err != nil {
+		return fmt.Errorf(KubeconfigFlagErrorMessage, err)
+	}
 
-	// Periodic reload every 2 seconds
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-	for range ticker.C {
-		applyConfig(configPath)
+	flags.Context, err = cmd.Flags().GetString("context")
+	if err != nil {
+		return fmt.Errorf(ContextFlagErrorMessage, err)
 	}
-}
 
-// applyConfig TODO - Reads the config file, parses durations,
-// and updates globals.ExecContext.Config
-func applyConfig(configPath string) {
-	configContent, err := config.ReadFile(configPath)
+	flags.QPS, err = cmd.Flags().GetFloat32("kube-api-qps")
 	if err != nil {
-		globals.ExecContext.Logger.Fatalf(fmt.Sprintf(ConfigNotParsedErrorMessage, err))
+		return fmt.Errorf(KubeAPIQPSFlagErrorMessage, err)
 	}
 
-	// Set default synchronization times if zero
-	if reflect.ValueOf(configContent.Spec.Synchronization.Time).IsZero() {
-		configContent.Spec.Synchronization.Time = v1alpha1.DefaultSyncTime
+	flags.Burst, err = cmd.Flags().GetInt("kube-api-burst")
+	if err != nil {
+		return fmt.Errorf(KubeAPIBurstFlagErrorMessage, err)
 	}
-	duration, err := time.ParseDuration(configContent.Spec.Synchronization.Time)
+
+	flags.CarriedTimeout, err = cmd.Flags().GetDuration("kube-api-timeout")
 	if err != nil {
-		globals.ExecContext.Logger.Fatalf(UnableParseDurationErrorMessage, err)
+		return fmt.Errorf(KubeAPITimeoutErrorMessage, err)
 	}
 
-	if reflect.ValueOf(configContent.Spec.Synchronization.ProcessingDelay).IsZero() {
-		configContent.Spec.Synchronization.ProcessingDelay = v1alpha1.DefaultSyncProcessingDelay
+	if flags.CarriedTimeout > 0 {
+		flags.Timeout = flags.CarriedTimeout.String()
 	}
-	durationDelay, err := time.ParseDuration(configContent.Spec.Synchronization.ProcessingDelay)
+
+	flags.UserAgent, err = cmd.Flags().GetString("user-agent")
 	if err != nil {
-		globals.ExecContext.Logger.Fatalf(UnableParseDurationErrorMessage, err)
+		return fmt.Errorf(UserAgentFlagErrorMessage, err)
 	}
 
-	configContent.Spec.Synchronization.CarriedTime = duration
-	configContent.Spec.Synchronization.CarriedProcessingDelay = durationDelay
+	return nil
+}
+
+// checkPermissions warns about the permissions needed by the config that are not granted to Hitman.
+// Only the config loaded on startup is checked, and any error is ignored as this is just a hint
+func checkPermissions(processors []*processor.Processor, includeController bool) {
+
+	// Following permissions are needed in the cluster where Hitman runs
+	configSnapshot := globals.ExecContext.Config.Load()
+	localPermissions := rbac.GetClustersPermissions(configSnapshot)
+
+	if includeController {
+		localPermissions = append(localPermissions, rbac.GetControllerPermissions()...)
+	}
+
+	localConfig, err := kubernetes.GetConfig(configSnapshot.Spec.Kubernetes)
+	if err == nil {
+		logMissingPermissions("", localConfig, localPermissions)
+	}
+
+	for _, processorObj := range processors {
+		permissions, _ := rbac.GetPermissions(configSnapshot, processorObj.Cluster.Name)
+
+		logMissingPermissions(processorObj.Cluster.Name, processorObj.RESTConfig, permissions)
+	}
+}
 
-	// Apply updated config under lock
-	globals.ExecContext.Config.Mutex.Lock()
+// logMissingPermissions logs the given permissions that are not granted to Hitman in a cluster
+func logMissingPermissions(clusterName string, restConfig *rest.Config, permissions []rbac.PermissionT) {
 
-	globals.ExecContext.Config.ApiVersion = configContent.ApiVersion
-	globals.ExecContext.Config.Kind = configContent.Kind
-	globals.ExecContext.Config.Metadata = configContent.Metadata
-	globals.ExecContext.Config.Spec = configContent.Spec
+	missingPermissions, err := rbac.GetMissingPermissions(restConfig, permissions)
+	if err != nil {
+		globals.ExecContext.Logger.Infow("error checking permissions", "cluster", clusterName, "error", err.Error())
+		return
+	}
+
+	for _, permission := range missingPermissions {
+		globals.ExecContext.Logger.Infow("permission not granted. Related rules will fail", "cluster", clusterName,
+			"verb", permission.Verb, "group", permission.Group, "resource", permission.Resource,
+			"resourceName", permission.ResourceName, "namespace", permission.Namespace)
+	}
+
+	if len(missingPermissions) > 0 {
+		globals.ExecContext.Logger.Infof("%d permissions are not granted. Run 'hitman rbac' to generate them",
+			len(missingPermissions))
+	}
+}
+
+// configProcessorWorker reads and applies the config initially, then reloads it every time the file changes.
+// Broken configs are rejected on reloads, keeping the last good one
+func configProcessorWorker(configPaths []string, configReady chan<- struct{}) {
+	// Hitman can not run without an initial config
+	err := applyConfig(configPaths)
+	if err != nil {
+		globals.ExecContext.Logger.Fatalf(ConfigNotParsedErrorMessage, err)
+	}
+
+	// Signal main that initial config is ready
+	close(configReady)
+
+	config.Watch(configPaths, func() {
+		err := applyConfig(configPaths)
+		if err != nil {
+			globals.ExecContext.Logger.Infow("error reloading config. Keeping previous one", "error", err.Error())
+		}
+	})
+}
+
+// applyConfig reads and validates the config file. Only valid configs replace the one in use
+func applyConfig(configPaths []string) (err error) {
+	defer func() {
+		metrics.RecordConfigReload(err == nil, float64(time.Now().Unix()))
+	}()
+
+	configContent, err := config.ReadFiles(configPaths)
+	if err != nil {
+		return err
+	}
+
+	err = config.ApplyDefaults(configContent)
+	if err != nil {
+		return err
+	}
+
+	changes := config.Store(configContent)
+	if len(changes) > 0 {
+		globals.ExecContext.Logger.Infow("config loaded", "rules", len(configContent.Spec.Resources), "changes", changes)
+	}
 
-	globals.ExecContext.Config.Mutex.Unlock()
+	return nil
 }
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	//
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	//
+	"hitman/internal/config"
+	"hitman/internal/schema"
+)
+
+const (
+	descriptionShort = `Print the JSON Schema of config files`
+	descriptionLong  = `
+	Schema print a JSON Schema for config files, generated from hitman API types.
+	It can be used by editors (e.g. with yaml-language-server) and linters to validate configs before deploying them:
+	hitman schema > hitman.schema.json
+	When --chart-values is set, the schema of the Helm chart values is printed instead, describing every apiVersion`
+
+	//
+	APIVersionFlagErrorMessage     = "impossible to get flag --api-version: %s"
+	ChartValuesFlagErrorMessage    = "impossible to get flag --chart-values: %s"
+	SchemaNotGeneratedErrorMessage = "impossible to generate JSON Schema: %s"
+	SchemaNotMarshalledMessage     = "impossible to marshal JSON Schema: %s"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "schema",
+		DisableFlagsInUseLine: true,
+		Short:                 descriptionShort,
+		Long:                  strings.ReplaceAll(descriptionLong, "\t", ""),
+
+		Run: RunCommand,
+	}
+
+	//
+	cmd.Flags().String("api-version", config.LatestAPIVersion, "apiVersion of the config files described by the schema")
+	cmd.Flags().Bool("chart-values", false, "Print the schema of the Helm chart values, where the config is embedded in every apiVersion")
+
+	return cmd
+}
+
+func RunCommand(cmd *cobra.Command, args []string) {
+
+	apiVersion, err := cmd.Flags().GetString("api-version")
+	if err != nil {
+		log.Fatalf(APIVersionFlagErrorMessage, err)
+	}
+
+	chartValues, err := cmd.Flags().GetBool("chart-values")
+	if err != nil {
+		log.Fatalf(ChartValuesFlagErrorMessage, err)
+	}
+
+	var jsonSchema apiextensionsv1.JSONSchemaProps
+	if chartValues {
+		jsonSchema, err = schema.GetChartValuesJSONSchema()
+	} else {
+		jsonSchema, err = schema.GetConfigJSONSchema(apiVersion)
+	}
+	if err != nil {
+		log.Fatalf(SchemaNotGeneratedErrorMessage, err)
+	}
+
+	jsonSchemaBytes, err := json.MarshalIndent(jsonSchema, "", "  ")
+	if err != nil {
+		log.Fatalf(SchemaNotMarshalledMessage, err)
+	}
+
+	fmt.Printf("%s\n", jsonSchemaBytes)
+}
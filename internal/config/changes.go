@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/rules"
+)
+
+// GetChanges return a human-readable description of the differences between two configs.
+// Rules are compared by name, so reordering them is not reported as a change
+func GetChanges(oldConfig *v1alpha1.ConfigT, newConfig *v1alpha1.ConfigT) (changes []string) {
+
+	globalSettings := []struct {
+		name     string
+		oldValue interface{}
+		newValue interface{}
+	}{
+		{"synchronization", oldConfig.Spec.Synchronization, newConfig.Spec.Synchronization},
+		{"kubernetes", oldConfig.Spec.Kubernetes, newConfig.Spec.Kubernetes},
+		{"clusters", oldConfig.Spec.Clusters, newConfig.Spec.Clusters},
+		{"audit", oldConfig.Spec.Audit, newConfig.Spec.Audit},
+		{"notifications", oldConfig.Spec.Notifications, newConfig.Spec.Notifications},
+		{"templates", oldConfig.Spec.Templates, newConfig.Spec.Templates},
+	}
+
+	for _, setting := range globalSettings {
+		if !reflect.DeepEqual(setting.oldValue, setting.newValue) {
+			changes = append(changes, fmt.Sprintf("%s changed", setting.name))
+		}
+	}
+
+	oldResources := getResourcesByName(oldConfig.Spec.Resources)
+	newResources := getResourcesByName(newConfig.Spec.Resources)
+
+	for resourceIndex, resource := range newConfig.Spec.Resources {
+		ruleName := rules.GetName(resourceIndex, resource)
+
+		oldResource, found := oldResources[ruleName]
+		switch {
+		case !found:
+			changes = append(changes, fmt.Sprintf("rule '%s' added", ruleName))
+		case !reflect.DeepEqual(oldResource, resource):
+			changes = append(changes, fmt.Sprintf("rule '%s' changed", ruleName))
+		}
+	}
+
+	for resourceIndex, resource := range oldConfig.Spec.Resources {
+		ruleName := rules.GetName(resourceIndex, resource)
+
+		if _, found := newResources[ruleName]; !found {
+			changes = append(changes, fmt.Sprintf("rule '%s' removed", ruleName))
+		}
+	}
+
+	return changes
+}
+
+// getResourcesByName indexes a list of resources by the name of their rules
+func getResourcesByName(resources []v1alpha1.ResourceT) map[string]v1alpha1.ResourceT {
+	resourcesByName := make(map[string]v1alpha1.ResourceT, len(resources))
+
+	for resourceIndex, resource := range resources {
+		resourcesByName[rules.GetName(resourceIndex, resource)] = resource
+	}
+
+	return resourcesByName
+}
@@ -4,11 +4,26 @@
 package config
 
 import (
-	"os"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+	"hitman/internal/rules"
+	"hitman/internal/scheduler"
+	"hitman/internal/template"
+)
+
+const (
+	// Name of the templates written in rules. Snippets can not use it
+	templateMainName = "main"
 )
 
 // Marshal TODO
@@ -17,21 +32,470 @@ func Marshal(config *v1alpha1.ConfigT) (bytes []byte, err error) {
 	return bytes, err
 }
 
-// Unmarshal TODO
+// Unmarshal decodes a config document of any supported version into the types used internally
 func Unmarshal(bytes []byte) (*v1alpha1.ConfigT, error) {
-	config := &v1alpha1.ConfigT{}
-	err := yaml.Unmarshal(bytes, config)
+	document := &yaml.Node{}
+	err := yaml.Unmarshal(bytes, document)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(document.Content) == 0 {
+		return nil, fmt.Errorf("config is empty")
+	}
+
+	config, _, err := decode(document.Content[0], "")
 	return config, err
 }
 
-// ReadFile TODO
+// ReadFile reads the config from a single path. It can be a file, a glob or a directory
 func ReadFile(filepath string) (*v1alpha1.ConfigT, error) {
-	var fileBytes []byte
-	fileBytes, err := os.ReadFile(filepath)
+	return ReadFiles([]string{filepath})
+}
+
+// ApplyDefaults fills the missing synchronization settings with default values, and parses the durations
+func ApplyDefaults(config *v1alpha1.ConfigT) (err error) {
+	if reflect.ValueOf(config.Spec.Synchronization.Time).IsZero() {
+		config.Spec.Synchronization.Time = v1alpha1.DefaultSyncTime
+	}
+	duration, err := time.ParseDuration(config.Spec.Synchronization.Time)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("unable to parse duration: %s", err.Error())
 	}
 
-	config, err := Unmarshal(fileBytes)
-	return config, err
+	if reflect.ValueOf(config.Spec.Synchronization.ProcessingDelay).IsZero() {
+		config.Spec.Synchronization.ProcessingDelay = v1alpha1.DefaultSyncProcessingDelay
+	}
+	durationDelay, err := time.ParseDuration(config.Spec.Synchronization.ProcessingDelay)
+	if err != nil {
+		return fmt.Errorf("unable to parse duration: %s", err.Error())
+	}
+
+	config.Spec.Synchronization.CarriedTime = duration
+	config.Spec.Synchronization.CarriedProcessingDelay = durationDelay
+
+	// Quarantine grace periods are carried along each resource
+	for resourceIndex := range config.Spec.Resources {
+		quarantine := &config.Spec.Resources[resourceIndex].Quarantine
+		if quarantine.GracePeriod == "" {
+			continue
+		}
+
+		quarantine.CarriedGracePeriod, err = time.ParseDuration(quarantine.GracePeriod)
+		if err != nil {
+			return fmt.Errorf("resources[%d]: unable to parse quarantine grace period: %s", resourceIndex, err.Error())
+		}
+	}
+
+	// Kubernetes client settings defined by flags take precedence over the config
+	err = applyKubernetesClientFlags(&config.Spec.Kubernetes, globals.ExecContext.KubernetesClientFlags)
+	if err != nil {
+		return err
+	}
+
+	err = validateRuleNames(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateRules(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateSchedules(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateTemplates(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateBackups(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateAudit(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateNotifications(config)
+	if err != nil {
+		return err
+	}
+
+	return validateClusters(config)
+}
+
+// validateRuleNames checks that every rule can be told apart from the rest in logs, metrics and status
+func validateRuleNames(config *v1alpha1.ConfigT) (err error) {
+	var ruleNames []string
+
+	for resourceIndex, resource := range config.Spec.Resources {
+		ruleName := rules.GetName(resourceIndex, resource)
+
+		if slices.Contains(ruleNames, ruleName) {
+			return fmt.Errorf("resources[%d]: name '%s' is duplicated", resourceIndex, ruleName)
+		}
+		ruleNames = append(ruleNames, ruleName)
+	}
+
+	return nil
+}
+
+// validateRules checks the targets and templates of the resources.
+// Config is validated entirely before being used, so a broken edit never replaces a working config
+func validateRules(config *v1alpha1.ConfigT) (err error) {
+	for resourceIndex, resource := range config.Spec.Resources {
+		target := resource.Target
+
+		if target.Version == "" || target.Resource == "" {
+			return fmt.Errorf("resources[%d]: target version and resource are required", resourceIndex)
+		}
+
+		// Matching a name is required. Without a namespace selector, objects of every namespace are matched
+		if target.Name.MatchExact == "" && target.Name.MatchRegex == "" {
+			return fmt.Errorf("resources[%d]: target name selector is missing", resourceIndex)
+		}
+
+		if target.Name.MatchExact != "" && target.Name.MatchRegex != "" {
+			return fmt.Errorf("resources[%d]: target name can only have one selector: matchExact or matchRegex", resourceIndex)
+		}
+
+		if target.Namespace.MatchExact != "" && target.Namespace.MatchRegex != "" {
+			return fmt.Errorf("resources[%d]: target namespace can only have one selector: matchExact or matchRegex", resourceIndex)
+		}
+
+		for _, regex := range []string{target.Name.MatchRegex, target.Namespace.MatchRegex} {
+			_, err = regexp.Compile(regex)
+			if err != nil {
+				return fmt.Errorf("resources[%d]: error compiling regular expression: %s", resourceIndex, err.Error())
+			}
+		}
+
+		if resource.Owners.ActOn != "" &&
+			resource.Owners.ActOn != v1alpha1.OwnersActOnObject &&
+			resource.Owners.ActOn != v1alpha1.OwnersActOnTopController {
+			return fmt.Errorf("resources[%d]: owners can only act on '%s' or '%s'",
+				resourceIndex, v1alpha1.OwnersActOnObject, v1alpha1.OwnersActOnTopController)
+		}
+
+		err = template.Validate(resource.PreStep)
+		if err != nil {
+			return fmt.Errorf("resources[%d]: error parsing preStep template: %s", resourceIndex, err.Error())
+		}
+
+		for conditionIndex, condition := range resource.Conditions {
+			err = template.Validate(condition.Key)
+			if err != nil {
+				return fmt.Errorf("resources[%d].conditions[%d]: error parsing template: %s", resourceIndex, conditionIndex, err.Error())
+			}
+		}
+
+		for conditionIndex, condition := range resource.Owners.HealthyConditions {
+			err = template.Validate(condition.Key)
+			if err != nil {
+				return fmt.Errorf("resources[%d].owners.healthyConditions[%d]: error parsing template: %s",
+					resourceIndex, conditionIndex, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTemplates checks the named snippets, and that every snippet included by a rule exists
+func validateTemplates(config *v1alpha1.ConfigT) (err error) {
+	if _, found := config.Spec.Templates[templateMainName]; found {
+		return fmt.Errorf("templates: name '%s' is reserved", templateMainName)
+	}
+
+	snippetTrees, err := template.ParseSnippets(config.Spec.Templates)
+	if err != nil {
+		return fmt.Errorf("templates: %s", err.Error())
+	}
+
+	checkIncludes := func(templateString string, location string) error {
+		includeCalls, err := template.FindFunctionCalls(templateString, "include")
+		if err != nil {
+			return fmt.Errorf("%s: error parsing template: %s", location, err.Error())
+		}
+
+		for _, includeCall := range includeCalls {
+			if len(includeCall.Arguments) == 0 || !includeCall.Literal[0] {
+				continue
+			}
+
+			if _, found := snippetTrees[includeCall.Arguments[0]]; !found {
+				return fmt.Errorf("%s: included template '%s' is not defined", location, includeCall.Arguments[0])
+			}
+		}
+		return nil
+	}
+
+	for name, snippet := range config.Spec.Templates {
+		err = checkIncludes(snippet, fmt.Sprintf("templates[%s]", name))
+		if err != nil {
+			return err
+		}
+	}
+
+	for resourceIndex, resource := range config.Spec.Resources {
+		templates := []string{resource.PreStep}
+		for _, condition := range slices.Concat(resource.Conditions, resource.Owners.HealthyConditions) {
+			templates = append(templates, condition.Key)
+		}
+
+		for _, templateString := range templates {
+			err = checkIncludes(templateString, fmt.Sprintf("resources[%d]", resourceIndex))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSchedules checks the schedules, timezones and windows of the resources.
+// Wrong ones would make their rules never run, so it's better to fail early
+func validateSchedules(config *v1alpha1.ConfigT) (err error) {
+	for resourceIndex, resource := range config.Spec.Resources {
+		_, err = scheduler.ParseSchedule(resource.Schedule, resource.Timezone, config.Spec.Synchronization.CarriedTime)
+		if err != nil {
+			return fmt.Errorf("resources[%d]: invalid schedule: %s", resourceIndex, err.Error())
+		}
+
+		for windowIndex, window := range resource.ActiveWindows {
+			err = scheduler.ValidateWindow(window)
+			if err != nil {
+				return fmt.Errorf("resources[%d].activeWindows[%d]: %s", resourceIndex, windowIndex, err.Error())
+			}
+		}
+
+		for windowIndex, window := range resource.BlackoutWindows {
+			err = scheduler.ValidateWindow(window)
+			if err != nil {
+				return fmt.Errorf("resources[%d].blackoutWindows[%d]: %s", resourceIndex, windowIndex, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyKubernetesClientFlags overrides the Kubernetes client settings of the config with the ones defined by flags,
+// and parses the durations that are carried along the config
+func applyKubernetesClientFlags(settings *v1alpha1.KubernetesClientT, flags v1alpha1.KubernetesClientT) (err error) {
+	if flags.Kubeconfig != "" {
+		settings.Kubeconfig = flags.Kubeconfig
+	}
+
+	if flags.Context != "" {
+		settings.Context = flags.Context
+	}
+
+	if flags.QPS > 0 {
+		settings.QPS = flags.QPS
+	}
+
+	if flags.Burst > 0 {
+		settings.Burst = flags.Burst
+	}
+
+	if flags.Timeout != "" {
+		settings.Timeout = flags.Timeout
+	}
+
+	if flags.UserAgent != "" {
+		settings.UserAgent = flags.UserAgent
+	}
+
+	settings.CarriedTimeout = 0
+	if settings.Timeout != "" {
+		settings.CarriedTimeout, err = time.ParseDuration(settings.Timeout)
+		if err != nil {
+			return fmt.Errorf("unable to parse duration: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateClusters checks that clusters have unique names, and rules are only scoped to declared clusters
+func validateClusters(config *v1alpha1.ConfigT) (err error) {
+	var clusterNames []string
+
+	for clusterIndex, cluster := range config.Spec.Clusters {
+		if cluster.Name == "" {
+			return fmt.Errorf("clusters[%d]: name is missing", clusterIndex)
+		}
+
+		if slices.Contains(clusterNames, cluster.Name) {
+			return fmt.Errorf("clusters[%d]: name '%s' is duplicated", clusterIndex, cluster.Name)
+		}
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+
+	// Without clusters, only the one where Hitman runs is processed
+	if len(clusterNames) == 0 {
+		clusterNames = append(clusterNames, v1alpha1.DefaultClusterName)
+	}
+
+	for resourceIndex, resource := range config.Spec.Resources {
+		for _, clusterName := range resource.Clusters {
+			if !slices.Contains(clusterNames, clusterName) {
+				return fmt.Errorf("resources[%d]: cluster '%s' is not declared", resourceIndex, clusterName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBackups checks where the objects of each resource are backed up.
+// A wrong destination would prevent every kill of the rule, as objects are never deleted without their backup
+func validateBackups(config *v1alpha1.ConfigT) (err error) {
+	for resourceIndex, resource := range config.Spec.Resources {
+		backup := resource.Backup
+
+		// Content of Secrets must never end up anywhere else
+		if backup.Type != "" && backup.Type != v1alpha1.BackupTypeSecret &&
+			resource.Target.Group == "" && resource.Target.Resource == "secrets" {
+			return fmt.Errorf("resources[%d]: Secrets can only be backed up with backup type '%s'",
+				resourceIndex, v1alpha1.BackupTypeSecret)
+		}
+
+		switch backup.Type {
+		case "":
+			continue
+
+		case v1alpha1.BackupTypeDirectory:
+			if backup.Path == "" {
+				return fmt.Errorf("resources[%d]: backup path is missing", resourceIndex)
+			}
+
+		case v1alpha1.BackupTypeConfigMap, v1alpha1.BackupTypeSecret:
+			if backup.Namespace == "" {
+				return fmt.Errorf("resources[%d]: backup namespace is missing", resourceIndex)
+			}
+
+			if messages := validation.IsDNS1123Label(backup.Namespace); len(messages) > 0 {
+				return fmt.Errorf("resources[%d]: backup namespace '%s' is not valid: %s", resourceIndex, backup.Namespace, messages[0])
+			}
+
+		default:
+			return fmt.Errorf("resources[%d]: backup type must be '%s', '%s' or '%s'", resourceIndex,
+				v1alpha1.BackupTypeDirectory, v1alpha1.BackupTypeConfigMap, v1alpha1.BackupTypeSecret)
+		}
+	}
+
+	return nil
+}
+
+// validateAudit checks the sinks where audit records are written
+func validateAudit(config *v1alpha1.ConfigT) (err error) {
+	for sinkIndex, sink := range config.Spec.Audit.Sinks {
+		switch sink.Type {
+		case v1alpha1.AuditSinkTypeStdout:
+
+		case v1alpha1.AuditSinkTypeFile:
+			if sink.File.Path == "" {
+				return fmt.Errorf("audit.sinks[%d]: file path is missing", sinkIndex)
+			}
+
+		case v1alpha1.AuditSinkTypeWebhook:
+			err = validateURL(sink.Webhook.URL)
+			if err != nil {
+				return fmt.Errorf("audit.sinks[%d]: webhook %s", sinkIndex, err.Error())
+			}
+
+			if sink.Webhook.Timeout != "" {
+				_, err = time.ParseDuration(sink.Webhook.Timeout)
+				if err != nil {
+					return fmt.Errorf("audit.sinks[%d]: unable to parse duration: %s", sinkIndex, err.Error())
+				}
+			}
+
+		default:
+			return fmt.Errorf("audit.sinks[%d]: type must be '%s', '%s' or '%s'", sinkIndex,
+				v1alpha1.AuditSinkTypeFile, v1alpha1.AuditSinkTypeStdout, v1alpha1.AuditSinkTypeWebhook)
+		}
+	}
+
+	return nil
+}
+
+// validateNotifications checks the settings of notifications, and the receivers they are sent to
+func validateNotifications(config *v1alpha1.ConfigT) (err error) {
+	notifications := config.Spec.Notifications
+
+	for _, duration := range []string{notifications.RetryBackoff, notifications.Timeout} {
+		if duration == "" {
+			continue
+		}
+
+		_, err = time.ParseDuration(duration)
+		if err != nil {
+			return fmt.Errorf("notifications: unable to parse duration: %s", err.Error())
+		}
+	}
+
+	for receiverIndex, receiver := range notifications.Receivers {
+		var receiverURL, receiverTemplate string
+
+		switch receiver.Type {
+		case v1alpha1.NotificationReceiverTypeWebhook:
+			receiverURL, receiverTemplate = receiver.Webhook.URL, receiver.Webhook.Body
+		case v1alpha1.NotificationReceiverTypeSlack:
+			receiverURL, receiverTemplate = receiver.Slack.URL, receiver.Slack.Text
+		case v1alpha1.NotificationReceiverTypeAlertmanager:
+			receiverURL = receiver.Alertmanager.URL
+		default:
+			return fmt.Errorf("notifications.receivers[%d]: type must be '%s', '%s' or '%s'", receiverIndex,
+				v1alpha1.NotificationReceiverTypeWebhook, v1alpha1.NotificationReceiverTypeSlack,
+				v1alpha1.NotificationReceiverTypeAlertmanager)
+		}
+
+		err = validateURL(receiverURL)
+		if err != nil {
+			return fmt.Errorf("notifications.receivers[%d]: %s %s", receiverIndex, receiver.Type, err.Error())
+		}
+
+		err = template.Validate(receiverTemplate)
+		if err != nil {
+			return fmt.Errorf("notifications.receivers[%d]: error parsing template: %s", receiverIndex, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateURL checks that a URL can be requested. The URL is never part of the error, as it can hold secrets
+func validateURL(rawURL string) (err error) {
+	if rawURL == "" {
+		return fmt.Errorf("url is missing")
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+
+	return nil
+}
+
+// Store replaces the config used by the whole application atomically, and return what changed.
+// Given config must not be modified afterwards, as it is shared with every reader
+func Store(config *v1alpha1.ConfigT) (changes []string) {
+	previousConfig := globals.ExecContext.Config.Swap(config)
+	if previousConfig == nil {
+		previousConfig = &v1alpha1.ConfigT{}
+	}
+
+	return GetChanges(previousConfig, config)
 }
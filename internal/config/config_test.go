@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+func TestValidateDestinations(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      v1alpha1.SpecificationT
+		wantError string
+	}{
+		{
+			name: "valid destinations",
+			spec: v1alpha1.SpecificationT{
+				Resources: []v1alpha1.ResourceT{
+					{Backup: v1alpha1.BackupT{Type: v1alpha1.BackupTypeDirectory, Path: "/backups"}},
+					{Backup: v1alpha1.BackupT{Type: v1alpha1.BackupTypeSecret, Namespace: "hitman"}},
+				},
+				Audit: v1alpha1.AuditT{Sinks: []v1alpha1.AuditSinkT{
+					{Type: v1alpha1.AuditSinkTypeStdout},
+					{Type: v1alpha1.AuditSinkTypeWebhook, Webhook: v1alpha1.AuditWebhookSinkT{URL: "https://audit.example.com", Timeout: "5s"}},
+				}},
+				Notifications: v1alpha1.NotificationsT{
+					Timeout: "10s",
+					Receivers: []v1alpha1.NotificationReceiverT{
+						{Type: v1alpha1.NotificationReceiverTypeSlack, Slack: v1alpha1.NotificationSlackT{URL: "https://hooks.example.com", Text: "{{ len .events }}"}},
+					},
+				},
+			},
+		},
+		{
+			name:      "unknown backup type",
+			spec:      v1alpha1.SpecificationT{Resources: []v1alpha1.ResourceT{{Backup: v1alpha1.BackupT{Type: "bucket"}}}},
+			wantError: "resources[0]: backup type must be",
+		},
+		{
+			name:      "backup directory without path",
+			spec:      v1alpha1.SpecificationT{Resources: []v1alpha1.ResourceT{{Backup: v1alpha1.BackupT{Type: v1alpha1.BackupTypeDirectory}}}},
+			wantError: "backup path is missing",
+		},
+		{
+			name:      "backup namespace is not valid",
+			spec:      v1alpha1.SpecificationT{Resources: []v1alpha1.ResourceT{{Backup: v1alpha1.BackupT{Type: v1alpha1.BackupTypeConfigMap, Namespace: "Hitman"}}}},
+			wantError: "backup namespace 'Hitman' is not valid",
+		},
+		{
+			name: "secrets backed up into configMaps",
+			spec: v1alpha1.SpecificationT{Resources: []v1alpha1.ResourceT{{
+				Target: v1alpha1.TargetT{Version: "v1", Resource: "secrets"},
+				Backup: v1alpha1.BackupT{Type: v1alpha1.BackupTypeConfigMap, Namespace: "hitman"},
+			}}},
+			wantError: "Secrets can only be backed up with backup type 'secret'",
+		},
+		{
+			name:      "unknown audit sink",
+			spec:      v1alpha1.SpecificationT{Audit: v1alpha1.AuditT{Sinks: []v1alpha1.AuditSinkT{{Type: "syslog"}}}},
+			wantError: "audit.sinks[0]: type must be",
+		},
+		{
+			name: "audit webhook with relative url",
+			spec: v1alpha1.SpecificationT{Audit: v1alpha1.AuditT{Sinks: []v1alpha1.AuditSinkT{
+				{Type: v1alpha1.AuditSinkTypeWebhook, Webhook: v1alpha1.AuditWebhookSinkT{URL: "audit.example.com/secret-token"}},
+			}}},
+			wantError: "url must be an absolute http or https URL",
+		},
+		{
+			name:      "notifications timeout",
+			spec:      v1alpha1.SpecificationT{Notifications: v1alpha1.NotificationsT{Timeout: "soon"}},
+			wantError: "notifications: unable to parse duration",
+		},
+		{
+			name: "notification receiver without url",
+			spec: v1alpha1.SpecificationT{Notifications: v1alpha1.NotificationsT{Receivers: []v1alpha1.NotificationReceiverT{
+				{Type: v1alpha1.NotificationReceiverTypeAlertmanager},
+			}}},
+			wantError: "notifications.receivers[0]: alertmanager url is missing",
+		},
+		{
+			name: "notification receiver with broken template",
+			spec: v1alpha1.SpecificationT{Notifications: v1alpha1.NotificationsT{Receivers: []v1alpha1.NotificationReceiverT{
+				{Type: v1alpha1.NotificationReceiverTypeWebhook, Webhook: v1alpha1.NotificationWebhookT{URL: "http://example.com", Body: "{{ .events"}},
+			}}},
+			wantError: "notifications.receivers[0]: error parsing template",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &v1alpha1.ConfigT{Spec: test.spec}
+
+			err := validateBackups(config)
+			if err == nil {
+				err = validateAudit(config)
+			}
+			if err == nil {
+				err = validateNotifications(config)
+			}
+
+			if test.wantError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if test.wantError != "" && (err == nil || !strings.Contains(err.Error(), test.wantError)) {
+				t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+			}
+
+			// URLs can hold tokens, so they are never part of the errors
+			if err != nil && strings.Contains(err.Error(), "secret-token") {
+				t.Fatalf("got error %v, which leaks the url", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	//
+	"gopkg.in/yaml.v3"
+
+	//
+	"hitman/internal/globals"
+)
+
+const (
+	// Prefix of the expressions replaced by the content of a file, e.g. '${file:/etc/hitman/secrets/token}'
+	expansionFilePrefix = "file:"
+
+	// Separator of the default value used when a variable is not set, e.g. '${HITMAN_NAMESPACE:-default}'
+	expansionDefaultSeparator = ":-"
+)
+
+var (
+	// DefaultConfigEnvAllowlist are the environment variables that can be expanded in config files by default
+	DefaultConfigEnvAllowlist = []string{"HITMAN_*"}
+
+	// expansionRegex matches '${...}' expressions, and their escaped form '$${...}'
+	expansionRegex = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+	// envNameRegex matches valid names for environment variables
+	envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// expandNode replaces the '${...}' expressions found in the values of a node by the content of
+// environment variables or files. This is done on values, once they are parsed, so the content can not
+// change the structure of the document. Paths of files are relative to the config file
+func (l *loaderT) expandNode(node *yaml.Node, file string) (err error) {
+
+	if node.Kind == yaml.ScalarNode {
+		expandedValue, err := l.expandString(node.Value, file)
+		if err != nil {
+			return err
+		}
+
+		// Plain values are resolved again, so '${HITMAN_MAX_DEPTH}' can be expanded into a number
+		if expandedValue != node.Value && node.Style == 0 {
+			node.Tag = ""
+		}
+		node.Value = expandedValue
+		return nil
+	}
+
+	for childIndex, child := range node.Content {
+		// Keys of the objects are not expanded
+		if node.Kind == yaml.MappingNode && childIndex%2 == 0 {
+			continue
+		}
+
+		err = l.expandNode(child, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expandString replaces the '${...}' expressions found in a string. Escaped ones are left as '${...}'.
+// Expressions not naming an allowed variable are left untouched too, as templates use the same syntax
+// for other purposes, e.g. '${1}' in the replacements of 'regexReplaceAll'
+func (l *loaderT) expandString(value string, file string) (expandedValue string, err error) {
+
+	expandedValue = expansionRegex.ReplaceAllStringFunc(value, func(expression string) string {
+		if err != nil {
+			return expression
+		}
+
+		if strings.HasPrefix(expression, "$$") {
+			return expression[1:]
+		}
+
+		content, expanded, expressionErr := l.getExpansionContent(expression[2:len(expression)-1], file)
+		if !expanded {
+			return expression
+		}
+
+		err = expressionErr
+		return content
+	})
+
+	return expandedValue, err
+}
+
+// getExpansionContent return the content an expression is replaced by, and whether it must be replaced at all.
+// Errors never include the content, as it can be a secret
+func (l *loaderT) getExpansionContent(expression string, file string) (content string, expanded bool, err error) {
+
+	if referencedFile, found := strings.CutPrefix(expression, expansionFilePrefix); found {
+		content, err = l.readExpansionFile(referencedFile, file)
+		return content, true, err
+	}
+
+	name, defaultValue, hasDefault := strings.Cut(expression, expansionDefaultSeparator)
+	if !envNameRegex.MatchString(name) || !isAllowed(name, globals.ExecContext.ConfigEnvAllowlist) {
+		return content, false, nil
+	}
+
+	content = os.Getenv(name)
+	if content == "" && hasDefault {
+		return defaultValue, true, nil
+	}
+
+	if _, found := os.LookupEnv(name); !found {
+		return content, true, fmt.Errorf("environment variable '%s' is not set", name)
+	}
+
+	return content, true, nil
+}
+
+// readExpansionFile return the content of a file referenced from a config file, without its trailing newline.
+// Only files allowed by the config file allowlist can be read. They are watched as part of the config,
+// so secrets are reloaded when they are rotated
+func (l *loaderT) readExpansionFile(referencedFile string, file string) (content string, err error) {
+	if referencedFile == "" {
+		return content, fmt.Errorf("invalid expression '${%s}': file path is missing", expansionFilePrefix)
+	}
+
+	if !filepath.IsAbs(referencedFile) {
+		referencedFile = filepath.Join(filepath.Dir(file), referencedFile)
+	}
+
+	referencedFile, err = filepath.Abs(referencedFile)
+	if err != nil {
+		return content, err
+	}
+
+	var allowedPaths []string
+	for _, allowedPath := range globals.ExecContext.ConfigFileAllowlist {
+		allowedPath, err = filepath.Abs(allowedPath)
+		if err != nil {
+			return content, err
+		}
+		allowedPaths = append(allowedPaths, allowedPath)
+	}
+
+	if !isAllowed(referencedFile, allowedPaths) {
+		return content, fmt.Errorf("file '%s' is not allowed. Allow it with --config-file-allowlist", referencedFile)
+	}
+
+	if !slices.Contains(l.files, referencedFile) {
+		l.files = append(l.files, referencedFile)
+	}
+
+	contentBytes, err := os.ReadFile(referencedFile)
+	if err != nil {
+		return content, err
+	}
+
+	return strings.TrimSuffix(string(contentBytes), "\n"), nil
+}
+
+// isAllowed return whether a name matches any of the patterns of an allowlist, e.g. 'HITMAN_*'
+func isAllowed(name string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		matched, err := filepath.Match(pattern, name)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	//
+	"hitman/internal/globals"
+)
+
+func TestExpandString(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"secrets/token": "s3cr3t\n",
+		"private/key":   "private\n",
+	})
+	file := filepath.Join(directory, "hitman.yaml")
+
+	t.Setenv("HITMAN_NAMESPACE", "team-a")
+	t.Setenv("HITMAN_EMPTY", "")
+	t.Setenv("HOME_SECRET", "leaked")
+
+	globals.ExecContext.ConfigEnvAllowlist = DefaultConfigEnvAllowlist
+	globals.ExecContext.ConfigFileAllowlist = []string{filepath.Join(directory, "secrets", "*")}
+	defer func() {
+		globals.ExecContext.ConfigEnvAllowlist = nil
+		globals.ExecContext.ConfigFileAllowlist = nil
+	}()
+
+	tests := []struct {
+		name      string
+		value     string
+		want      string
+		wantError string
+	}{
+		{"variable", "ns-${HITMAN_NAMESPACE}", "ns-team-a", ""},
+		{"default of unset variable", "${HITMAN_UNSET:-default}", "default", ""},
+		{"default of empty variable", "${HITMAN_EMPTY:-default}", "default", ""},
+		{"default of set variable", "${HITMAN_NAMESPACE:-default}", "team-a", ""},
+		{"unset variable", "${HITMAN_UNSET}", "", "environment variable 'HITMAN_UNSET' is not set"},
+		{"escaped expression", "$${HITMAN_NAMESPACE}", "${HITMAN_NAMESPACE}", ""},
+		{"variable out of the allowlist", "${HOME_SECRET}", "${HOME_SECRET}", ""},
+		{"regex replacement", `{{ regexReplaceAll "^(.*)-x$" .name "${1}" }}`, `{{ regexReplaceAll "^(.*)-x$" .name "${1}" }}`, ""},
+		{"file", "Bearer ${file:secrets/token}", "Bearer s3cr3t", ""},
+		{"file out of the allowlist", "${file:private/key}", "", "is not allowed"},
+		{"file without path", "${file:}", "", "file path is missing"},
+		{"several expressions", "${HITMAN_NAMESPACE}/${file:secrets/token}", "team-a/s3cr3t", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loader := &loaderT{}
+
+			result, err := loader.expandString(test.value, file)
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if result != test.want {
+				t.Fatalf("got '%s', want '%s'", result, test.want)
+			}
+		})
+	}
+}
+
+func TestExpandNodeTypes(t *testing.T) {
+	t.Setenv("HITMAN_MAX_DEPTH", "7")
+	t.Setenv("HITMAN_ENABLED", "false")
+
+	globals.ExecContext.ConfigEnvAllowlist = DefaultConfigEnvAllowlist
+	defer func() { globals.ExecContext.ConfigEnvAllowlist = nil }()
+
+	directory := writeFiles(t, map[string]string{
+		"hitman.yaml": `
+apiVersion: hitman.io/v1alpha2
+kind: Hitman
+metadata:
+  name: main
+spec:
+  synchronization:
+    time: 1m
+  rules:
+    - name: ${HITMAN_MAX_DEPTH}
+      enabled: ${HITMAN_ENABLED}
+      owners:
+        maxDepth: ${HITMAN_MAX_DEPTH}
+`,
+	})
+
+	config, err := ReadFiles([]string{filepath.Join(directory, "hitman.yaml")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resource := config.Spec.Resources[0]
+	if resource.Name != "7" || resource.Owners.MaxDepth != 7 || resource.Enabled == nil || *resource.Enabled {
+		t.Fatalf("got name '%s', maxDepth %d and enabled %v, want '7', 7 and false",
+			resource.Name, resource.Owners.MaxDepth, resource.Enabled)
+	}
+}
@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	//
+	"gopkg.in/yaml.v3"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+const (
+	// Key of the YAML objects replaced by the content of another file
+	refKey = "$ref"
+)
+
+// loaderT reads several config files, following their includes and references, and merges them
+type loaderT struct {
+	// Every file read, in the order they were read
+	files []string
+
+	// Merged config, and the files each global setting was taken from
+	config          *v1alpha1.ConfigT
+	settingsSources map[string]string
+}
+
+// ReadFiles reads and merges the config from several paths. Each one can be a file, a glob or a directory.
+// Resources of every file are appended in order. Global settings can be defined in any file,
+// but defining them differently in several files is an error
+func ReadFiles(paths []string) (*v1alpha1.ConfigT, error) {
+	loader := &loaderT{config: &v1alpha1.ConfigT{}, settingsSources: map[string]string{}}
+
+	err := loader.loadPaths(paths, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.config, nil
+}
+
+// GetFiles return every file involved in the config read from several paths, including those
+// included or referenced from others
+func GetFiles(paths []string) (files []string, err error) {
+	loader := &loaderT{config: &v1alpha1.ConfigT{}, settingsSources: map[string]string{}}
+
+	err = loader.loadPaths(paths, "", "")
+	return loader.files, err
+}
+
+// ExpandPaths converts a list of files, globs and directories into the list of files they point to.
+// Directories are not read recursively, and only YAML files not hidden are taken from them.
+// Relative paths are resolved from baseDirectory
+func ExpandPaths(paths []string, baseDirectory string) (files []string, err error) {
+
+	for _, path := range paths {
+		if baseDirectory != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(baseDirectory, path)
+		}
+
+		if strings.ContainsAny(path, "*?[") {
+			matches, err := filepath.Glob(path)
+			if err != nil {
+				return files, fmt.Errorf("invalid pattern '%s': %s", path, err.Error())
+			}
+
+			if len(matches) == 0 {
+				return files, fmt.Errorf("pattern '%s' matches no files", path)
+			}
+
+			files = append(files, matches...)
+			continue
+		}
+
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			return files, err
+		}
+
+		if !fileInfo.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		// ConfigMaps mounted as volumes contain hidden directories with the same files. They are skipped
+		directoryEntries, err := os.ReadDir(path)
+		if err != nil {
+			return files, err
+		}
+
+		var directoryFiles []string
+		for _, entry := range directoryEntries {
+			extension := filepath.Ext(entry.Name())
+			if strings.HasPrefix(entry.Name(), ".") || (extension != ".yaml" && extension != ".yml") {
+				continue
+			}
+
+			entryPath := filepath.Join(path, entry.Name())
+			entryInfo, err := os.Stat(entryPath)
+			if err != nil || entryInfo.IsDir() {
+				continue
+			}
+
+			directoryFiles = append(directoryFiles, entryPath)
+		}
+		sort.Strings(directoryFiles)
+
+		if len(directoryFiles) == 0 {
+			return files, fmt.Errorf("directory '%s' contains no YAML files", path)
+		}
+
+		files = append(files, directoryFiles...)
+	}
+
+	return files, nil
+}
+
+// loadPaths reads the files pointed by several paths, merging them into the config.
+// Files not declaring apiVersion and kind are read with the given default version.
+// All of them are read before merging any, so files referenced by others are never loaded as config files,
+// whatever their names are (e.g. snippets living in the same directory as the files referencing them).
+// Files are only loaded once, so including them several times (or in cycles) is harmless
+func (l *loaderT) loadPaths(paths []string, baseDirectory string, defaultAPIVersion string) (err error) {
+	files, err := ExpandPaths(paths, baseDirectory)
+	if err != nil {
+		return err
+	}
+
+	fileNodes := make([]*yaml.Node, len(files))
+	for fileIndex, file := range files {
+		files[fileIndex] = filepath.Clean(file)
+		if slices.Contains(l.files, files[fileIndex]) {
+			continue
+		}
+
+		fileNodes[fileIndex], err = l.readYAMLFile(files[fileIndex], nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	for fileIndex, file := range files {
+		if fileNodes[fileIndex] == nil || slices.Contains(l.files, file) {
+			continue
+		}
+
+		err = l.loadFile(file, fileNodes[fileIndex], defaultAPIVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadFile merges an already read config file into the config. Its includes are loaded right after it,
+// and they inherit its version when they do not declare one
+func (l *loaderT) loadFile(file string, fileNode *yaml.Node, defaultAPIVersion string) (err error) {
+	l.files = append(l.files, file)
+
+	fileConfig, apiVersion, err := decode(fileNode, defaultAPIVersion)
+	if err != nil {
+		return fmt.Errorf("error parsing '%s': %s", file, err.Error())
+	}
+
+	err = l.merge(file, fileConfig)
+	if err != nil {
+		return err
+	}
+
+	err = l.loadPaths(fileConfig.Include, filepath.Dir(file), apiVersion)
+	if err != nil {
+		return fmt.Errorf("error including files from '%s': %s", file, err.Error())
+	}
+
+	return nil
+}
+
+// readYAMLFile reads a YAML file replacing its expressions and references by the content they point to
+func (l *loaderT) readYAMLFile(file string, referenceChain []string) (node *yaml.Node, err error) {
+	fileBytes, err := os.ReadFile(file)
+	if err != nil {
+		return node, err
+	}
+
+	document := &yaml.Node{}
+	err = yaml.Unmarshal(fileBytes, document)
+	if err != nil {
+		return node, fmt.Errorf("error parsing '%s': %s", file, err.Error())
+	}
+
+	// Empty files are empty documents
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+
+	node = document.Content[0]
+	err = l.expandNode(node, file)
+	if err != nil {
+		return node, fmt.Errorf("error expanding '%s': %s", file, err.Error())
+	}
+
+	err = l.resolveReferences(node, file, append(referenceChain, file))
+	return node, err
+}
+
+// resolveReferences replaces every '{$ref: path}' object found inside a node by the content of the file.
+// YAML files are inserted as YAML, and the rest of files (e.g. templates) are inserted as strings.
+// Paths are relative to the file the reference is found in
+func (l *loaderT) resolveReferences(node *yaml.Node, file string, referenceChain []string) (err error) {
+
+	for childIndex, child := range node.Content {
+		isReference := child.Kind == yaml.MappingNode && len(child.Content) == 2 &&
+			child.Content[0].Value == refKey && child.Content[1].Kind == yaml.ScalarNode
+
+		if !isReference {
+			err = l.resolveReferences(child, file, referenceChain)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		referencedFile := child.Content[1].Value
+		if !filepath.IsAbs(referencedFile) {
+			referencedFile = filepath.Join(filepath.Dir(file), referencedFile)
+		}
+		referencedFile = filepath.Clean(referencedFile)
+
+		if slices.Contains(referenceChain, referencedFile) {
+			return fmt.Errorf("error resolving reference to '%s' from '%s': references are cyclic", referencedFile, file)
+		}
+
+		if !slices.Contains(l.files, referencedFile) {
+			l.files = append(l.files, referencedFile)
+		}
+
+		var referencedNode *yaml.Node
+
+		switch filepath.Ext(referencedFile) {
+		case ".yaml", ".yml":
+			referencedNode, err = l.readYAMLFile(referencedFile, referenceChain)
+		default:
+			var referencedBytes []byte
+			referencedBytes, err = os.ReadFile(referencedFile)
+			referencedNode = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str",
+				Value: strings.TrimSuffix(string(referencedBytes), "\n")}
+		}
+
+		if err != nil {
+			return fmt.Errorf("error resolving reference to '%s' from '%s': %s", referencedFile, file, err.Error())
+		}
+
+		node.Content[childIndex] = referencedNode
+	}
+
+	return nil
+}
+
+// merge adds the content of a config file into the config
+func (l *loaderT) merge(file string, fileConfig *v1alpha1.ConfigT) (err error) {
+
+	// Identity of the config is taken from the first file defining it
+	if l.config.ApiVersion == "" {
+		l.config.ApiVersion = fileConfig.ApiVersion
+	}
+
+	if l.config.Kind == "" {
+		l.config.Kind = fileConfig.Kind
+	}
+
+	if l.config.Metadata.Name == "" {
+		l.config.Metadata = fileConfig.Metadata
+	}
+
+	globalSettings := []struct {
+		name      string
+		fileValue interface{}
+		value     interface{}
+	}{
+		{"synchronization", &fileConfig.Spec.Synchronization, &l.config.Spec.Synchronization},
+		{"kubernetes", &fileConfig.Spec.Kubernetes, &l.config.Spec.Kubernetes},
+		{"clusters", &fileConfig.Spec.Clusters, &l.config.Spec.Clusters},
+		{"audit", &fileConfig.Spec.Audit, &l.config.Spec.Audit},
+		{"notifications", &fileConfig.Spec.Notifications, &l.config.Spec.Notifications},
+	}
+
+	for _, setting := range globalSettings {
+		fileValue := reflect.ValueOf(setting.fileValue).Elem()
+		value := reflect.ValueOf(setting.value).Elem()
+
+		if fileValue.IsZero() {
+			continue
+		}
+
+		source, found := l.settingsSources[setting.name]
+		if !found {
+			value.Set(fileValue)
+			l.settingsSources[setting.name] = file
+			continue
+		}
+
+		if !reflect.DeepEqual(fileValue.Interface(), value.Interface()) {
+			return fmt.Errorf("conflicting %s settings: defined differently in '%s' and '%s'", setting.name, source, file)
+		}
+	}
+
+	// Templates can be spread across files, as long as the same name is not defined differently
+	for name, snippet := range fileConfig.Spec.Templates {
+		settingName := fmt.Sprintf("templates[%s]", name)
+
+		source, found := l.settingsSources[settingName]
+		if found && l.config.Spec.Templates[name] != snippet {
+			return fmt.Errorf("conflicting %s settings: defined differently in '%s' and '%s'", settingName, source, file)
+		}
+
+		if found {
+			continue
+		}
+
+		if l.config.Spec.Templates == nil {
+			l.config.Spec.Templates = map[string]string{}
+		}
+		l.config.Spec.Templates[name] = snippet
+		l.settingsSources[settingName] = file
+	}
+
+	l.config.Spec.Resources = append(l.config.Spec.Resources, fileConfig.Spec.Resources...)
+
+	return nil
+}
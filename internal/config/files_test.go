@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates the given files, indexed by their paths relative to a temporary directory
+func writeFiles(t *testing.T, files map[string]string) (directory string) {
+	t.Helper()
+
+	directory = t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(directory, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0750)
+		if err != nil {
+			t.Fatalf("error creating directory: %s", err)
+		}
+
+		err = os.WriteFile(path, []byte(content), 0640)
+		if err != nil {
+			t.Fatalf("error writing file: %s", err)
+		}
+	}
+
+	return directory
+}
+
+func TestReadFiles(t *testing.T) {
+	const mainFile = `
+apiVersion: hitman.io/v1alpha2
+kind: Hitman
+metadata:
+  name: main
+spec:
+  synchronization:
+    time: 1m
+`
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		paths     []string
+		wantRules []string
+		wantError string
+	}{
+		{
+			name: "includes inherit the version and are merged in order",
+			files: map[string]string{
+				"hitman.yaml": mainFile + "include: [./teams]\n",
+				"teams/b.yaml": `
+spec:
+  rules:
+    - name: b
+`,
+				"teams/a.yaml": `
+spec:
+  rules:
+    - name: a
+`,
+			},
+			paths:     []string{"hitman.yaml"},
+			wantRules: []string{"a", "b"},
+		},
+		{
+			name: "references insert YAML and text files",
+			files: map[string]string{
+				"hitman.yaml": mainFile + `
+  rules:
+    - name: pods
+      conditions:
+        - key: {$ref: snippets/old.tpl}
+          value: "true"
+    - {$ref: snippets/jobs.yaml}
+`,
+				"snippets/old.tpl":   "{{ .object | olderThan \"10m\" }}\n",
+				"snippets/jobs.yaml": "name: jobs\n",
+			},
+			paths:     []string{"hitman.yaml"},
+			wantRules: []string{"pods", "jobs"},
+		},
+		{
+			name: "referenced files sorted before the referencing one are not loaded as configs",
+			files: map[string]string{
+				"z-hitman.yaml": mainFile + "  rules:\n    - {$ref: a-rule.yaml}\n",
+				"a-rule.yaml":   "name: shared\n",
+			},
+			paths:     []string{"."},
+			wantRules: []string{"shared"},
+		},
+		{
+			name: "referenced files sorted after the referencing one are not loaded as configs",
+			files: map[string]string{
+				"a-hitman.yaml": mainFile + "  rules:\n    - {$ref: z-rule.yaml}\n",
+				"z-rule.yaml":   "name: shared\n",
+			},
+			paths:     []string{"."},
+			wantRules: []string{"shared"},
+		},
+		{
+			name: "cyclic references are rejected",
+			files: map[string]string{
+				"hitman.yaml": mainFile + "  rules:\n    - {$ref: a.yaml}\n",
+				"a.yaml":      "name: {$ref: b.yaml}\n",
+				"b.yaml":      "- {$ref: a.yaml}\n",
+			},
+			paths:     []string{"hitman.yaml"},
+			wantError: "references are cyclic",
+		},
+		{
+			name: "global settings defined differently are rejected",
+			files: map[string]string{
+				"a.yaml": mainFile,
+				"b.yaml": strings.ReplaceAll(mainFile, "1m", "5m"),
+			},
+			paths:     []string{"a.yaml", "b.yaml"},
+			wantError: "conflicting synchronization settings",
+		},
+		{
+			name: "templates defined differently are rejected",
+			files: map[string]string{
+				"a.yaml": mainFile + "  templates:\n    old: a\n",
+				"b.yaml": mainFile + "  templates:\n    old: b\n",
+			},
+			paths:     []string{"a.yaml", "b.yaml"},
+			wantError: "conflicting templates[old] settings",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			directory := writeFiles(t, test.files)
+
+			var paths []string
+			for _, path := range test.paths {
+				paths = append(paths, filepath.Join(directory, path))
+			}
+
+			config, err := ReadFiles(paths)
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var rules []string
+			for _, resource := range config.Spec.Resources {
+				rules = append(rules, resource.Name)
+			}
+
+			if strings.Join(rules, ",") != strings.Join(test.wantRules, ",") {
+				t.Fatalf("got rules %v, want %v", rules, test.wantRules)
+			}
+		})
+	}
+}
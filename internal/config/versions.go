@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	//
+	"gopkg.in/yaml.v3"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/api/v1alpha2"
+)
+
+var (
+	APIVersionV1alpha1 = v1alpha1.Group + "/" + v1alpha1.Version
+	APIVersionV1alpha2 = v1alpha2.Group + "/" + v1alpha2.Version
+
+	// LatestAPIVersion is the version new configs should be written in
+	LatestAPIVersion = APIVersionV1alpha2
+)
+
+// headerT defines the fields needed to know how to decode a config document
+type headerT struct {
+	ApiVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+
+	// Old configs used this field by mistake. It is detected to give a helpful error
+	Version string `yaml:"version"`
+}
+
+// decode converts a config document of any supported version into the types used internally.
+// Documents without apiVersion and kind are decoded with the given default version, if any.
+// Unknown fields are rejected, so typos are not silently ignored
+func decode(node *yaml.Node, defaultAPIVersion string) (config *v1alpha1.ConfigT, apiVersion string, err error) {
+
+	header := headerT{}
+	err = node.Decode(&header)
+	if err != nil {
+		return config, apiVersion, err
+	}
+
+	if header.Version != "" && header.ApiVersion == "" {
+		return config, apiVersion, fmt.Errorf("field 'version' is not supported. Use 'apiVersion: %s' instead", LatestAPIVersion)
+	}
+
+	apiVersion = header.ApiVersion
+	if apiVersion == "" && header.Kind == "" {
+		apiVersion = defaultAPIVersion
+		header.Kind = v1alpha1.KindHitman
+	}
+
+	if apiVersion == "" {
+		return config, apiVersion, fmt.Errorf("field 'apiVersion' is missing. Supported ones are: %s",
+			strings.Join([]string{APIVersionV1alpha1, APIVersionV1alpha2}, ", "))
+	}
+
+	if header.Kind != v1alpha1.KindHitman {
+		return config, apiVersion, fmt.Errorf("kind '%s' is not supported. It must be '%s'", header.Kind, v1alpha1.KindHitman)
+	}
+
+	switch apiVersion {
+	case APIVersionV1alpha1:
+		config = &v1alpha1.ConfigT{}
+		err = decodeStrict(node, config)
+
+	case APIVersionV1alpha2:
+		versionedConfig := &v1alpha2.ConfigT{}
+		err = decodeStrict(node, versionedConfig)
+		config = v1alpha2.ConvertToV1alpha1(versionedConfig)
+
+	default:
+		return config, apiVersion, fmt.Errorf("apiVersion '%s' is not supported. Supported ones are: %s",
+			apiVersion, strings.Join([]string{APIVersionV1alpha1, APIVersionV1alpha2}, ", "))
+	}
+
+	if err != nil {
+		return config, apiVersion, err
+	}
+
+	config.Kind = v1alpha1.KindHitman
+	return config, apiVersion, nil
+}
+
+// decodeStrict decodes a YAML node into the given type, failing on unknown fields.
+// YAML nodes can not be decoded strictly, so they are encoded again first
+func decodeStrict(node *yaml.Node, out interface{}) (err error) {
+	nodeBytes, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(nodeBytes))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(out)
+	if err != nil {
+		// Errors are prefixed with a generic header that adds nothing
+		return fmt.Errorf("%s", strings.TrimPrefix(err.Error(), "yaml: unmarshal errors:\n  "))
+	}
+
+	return nil
+}
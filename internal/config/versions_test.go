@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	//
+	"gopkg.in/yaml.v3"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name              string
+		document          string
+		defaultAPIVersion string
+		wantAPIVersion    string
+		wantRules         []string
+		wantError         string
+	}{
+		{
+			name:           "v1alpha1 reads resources",
+			document:       "apiVersion: hitman.io/v1alpha1\nkind: Hitman\nspec:\n  resources:\n    - name: a\n",
+			wantAPIVersion: APIVersionV1alpha1,
+			wantRules:      []string{"a"},
+		},
+		{
+			name:           "v1alpha2 reads rules",
+			document:       "apiVersion: hitman.io/v1alpha2\nkind: Hitman\nspec:\n  rules:\n    - name: a\n    - name: b\n",
+			wantAPIVersion: APIVersionV1alpha2,
+			wantRules:      []string{"a", "b"},
+		},
+		{
+			name:              "documents without header use the default version",
+			document:          "spec:\n  rules:\n    - name: a\n",
+			defaultAPIVersion: APIVersionV1alpha2,
+			wantAPIVersion:    APIVersionV1alpha2,
+			wantRules:         []string{"a"},
+		},
+		{
+			name:      "documents without header nor default version",
+			document:  "spec:\n  rules: []\n",
+			wantError: "field 'apiVersion' is missing",
+		},
+		{
+			name:      "old version field",
+			document:  "version: v1alpha1\nkind: Hitman\n",
+			wantError: "field 'version' is not supported",
+		},
+		{
+			name:      "unsupported kind",
+			document:  "apiVersion: hitman.io/v1alpha2\nkind: Other\n",
+			wantError: "kind 'Other' is not supported",
+		},
+		{
+			name:      "unsupported version",
+			document:  "apiVersion: hitman.io/v9\nkind: Hitman\n",
+			wantError: "apiVersion 'hitman.io/v9' is not supported",
+		},
+		{
+			name:      "v1alpha2 rejects resources",
+			document:  "apiVersion: hitman.io/v1alpha2\nkind: Hitman\nspec:\n  resources: []\n",
+			wantError: "field resources not found",
+		},
+		{
+			name:      "v1alpha1 rejects rules",
+			document:  "apiVersion: hitman.io/v1alpha1\nkind: Hitman\nspec:\n  rules: []\n",
+			wantError: "field rules not found",
+		},
+		{
+			name:      "typos are rejected",
+			document:  "apiVersion: hitman.io/v1alpha2\nkind: Hitman\nspec:\n  rules:\n    - name: a\n      conditons: []\n",
+			wantError: "field conditons not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			document := &yaml.Node{}
+			err := yaml.Unmarshal([]byte(test.document), document)
+			if err != nil {
+				t.Fatalf("error parsing document: %s", err)
+			}
+
+			config, apiVersion, err := decode(document.Content[0], test.defaultAPIVersion)
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if apiVersion != test.wantAPIVersion {
+				t.Fatalf("got apiVersion '%s', want '%s'", apiVersion, test.wantAPIVersion)
+			}
+
+			// Hitman works with v1alpha1 types whatever the version of the document is
+			if apiVersion == APIVersionV1alpha2 && config.ApiVersion != APIVersionV1alpha1 {
+				t.Fatalf("got config in '%s', want it converted to '%s'", config.ApiVersion, APIVersionV1alpha1)
+			}
+
+			var rules []string
+			for _, resource := range config.Spec.Resources {
+				rules = append(rules, resource.Name)
+			}
+
+			if strings.Join(rules, ",") != strings.Join(test.wantRules, ",") {
+				t.Fatalf("got rules %v, want %v", rules, test.wantRules)
+			}
+		})
+	}
+}
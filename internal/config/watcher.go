@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	//
+	"github.com/fsnotify/fsnotify"
+
+	//
+	"hitman/internal/globals"
+)
+
+const (
+	// Editors and Kubernetes perform several operations per change. They are grouped into a single reload
+	watchDebounceTime = 500 * time.Millisecond
+
+	// Files are polled when they can not be watched (e.g. inotify limits reached)
+	watchPollInterval = 2 * time.Second
+)
+
+// Watch blocks calling onChange every time the config read from several paths changes, until the context is done.
+// Directories are watched instead of files, so files added to them are noticed. Besides, ConfigMaps mounted
+// as volumes are updated swapping symlinks, and editors usually replace files instead of writing them
+func Watch(paths []string, onChange func()) {
+	lastContent := getContent(paths)
+
+	// Content is compared, so events not changing the files do not trigger reloads
+	notifyIfChanged := func() {
+		content := getContent(paths)
+		if bytes.Equal(content, lastContent) {
+			return
+		}
+		lastContent = content
+		onChange()
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = addWatchedDirectories(watcher, paths)
+
+		// Directories already added would keep their inotify watches, and the events channel would fill up unread
+		if err != nil {
+			_ = watcher.Close()
+		}
+	}
+
+	if err != nil {
+		globals.ExecContext.Logger.Infof("error watching config files. Polling them every %s instead: %s",
+			watchPollInterval.String(), err.Error())
+
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-globals.ExecContext.Context.Done():
+				return
+			case <-ticker.C:
+				notifyIfChanged()
+			}
+		}
+	}
+	defer watcher.Close()
+
+	debounceTimer := time.NewTimer(watchDebounceTime)
+	debounceTimer.Stop()
+
+	for {
+		select {
+		case <-globals.ExecContext.Context.Done():
+			return
+
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			debounceTimer.Reset(watchDebounceTime)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			globals.ExecContext.Logger.Infof("error watching config files: %s", err.Error())
+
+		case <-debounceTimer.C:
+			// Symlinks can point to another directory after a swap, and new files can be included
+			_ = addWatchedDirectories(watcher, paths)
+			notifyIfChanged()
+		}
+	}
+}
+
+// getWatchedFiles return the files the config is read from. When the config is broken,
+// only the files pointed by the paths are known
+func getWatchedFiles(paths []string) (files []string) {
+	files, err := GetFiles(paths)
+	if err != nil {
+		files, _ = ExpandPaths(paths, "")
+	}
+
+	return files
+}
+
+// getContent return the content of all the files the config is read from, including their names
+func getContent(paths []string) (content []byte) {
+	for _, file := range getWatchedFiles(paths) {
+		fileBytes, _ := os.ReadFile(file)
+		content = append(content, file...)
+		content = append(content, fileBytes...)
+	}
+
+	return content
+}
+
+// addWatchedDirectories watches the directories of the paths, and the directories of the real files
+// behind them, following symlinks. Only the directories of the paths must exist
+func addWatchedDirectories(watcher *fsnotify.Watcher, paths []string) (err error) {
+	for _, path := range paths {
+		directory := filepath.Dir(path)
+
+		fileInfo, statErr := os.Stat(path)
+		if statErr == nil && fileInfo.IsDir() {
+			directory = path
+		}
+
+		// Directories matched by globs are watched through the files found inside them
+		if strings.ContainsAny(directory, "*?[") {
+			continue
+		}
+
+		// Watching an already watched directory is a no-op
+		err = watcher.Add(directory)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, file := range getWatchedFiles(paths) {
+		_ = watcher.Add(filepath.Dir(file))
+
+		realPath, err := filepath.EvalSymlinks(file)
+		if err == nil {
+			_ = watcher.Add(filepath.Dir(realPath))
+		}
+	}
+
+	return nil
+}
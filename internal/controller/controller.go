@@ -0,0 +1,420 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	//
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/config"
+	"hitman/internal/globals"
+	"hitman/internal/kubernetes"
+	"hitman/internal/metrics"
+	"hitman/internal/rules"
+)
+
+var (
+	HitmanGVR = schema.GroupVersionResource{
+		Group:    v1alpha1.Group,
+		Version:  v1alpha1.Version,
+		Resource: v1alpha1.ResourceHitman,
+	}
+
+	HitmanPolicyGVR = schema.GroupVersionResource{
+		Group:    v1alpha1.Group,
+		Version:  v1alpha1.Version,
+		Resource: v1alpha1.ResourceHitmanPolicy,
+	}
+)
+
+// Controller watches Hitman and HitmanPolicy objects in Kubernetes.
+// All of them are merged into the config used by the whole application, and their status is reported back
+type Controller struct {
+	Client *dynamic.DynamicClient
+
+	informerFactory      dynamicinformer.DynamicSharedInformerFactory
+	hitmanInformer       informers.GenericInformer
+	hitmanPolicyInformer informers.GenericInformer
+
+	// Reconciliations are triggered from several informers
+	reconcileMutex sync.Mutex
+}
+
+func NewController() (controller *Controller, err error) {
+
+	// Config is not read yet, so only the client settings defined by flags are known
+	restConfig, err := kubernetes.GetConfig(globals.ExecContext.KubernetesClientFlags)
+	if err != nil {
+		return controller, err
+	}
+
+	client, err := kubernetes.NewClient(restConfig)
+	if err != nil {
+		return controller, err
+	}
+
+	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+
+	return &Controller{
+		Client:               client,
+		informerFactory:      informerFactory,
+		hitmanInformer:       informerFactory.ForResource(HitmanGVR),
+		hitmanPolicyInformer: informerFactory.ForResource(HitmanPolicyGVR),
+	}, err
+}
+
+// Start begins watching the objects. It blocks until the informers are synced and the config is built once
+func (c *Controller) Start() (err error) {
+
+	eventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.reconcile()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Status updates are performed by Hitman itself and do not change the generation. Ignore them
+			oldObject, oldOk := oldObj.(*unstructured.Unstructured)
+			newObject, newOk := newObj.(*unstructured.Unstructured)
+			if oldOk && newOk && oldObject.GetGeneration() == newObject.GetGeneration() {
+				return
+			}
+			c.reconcile()
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.reconcile()
+		},
+	}
+
+	_, err = c.hitmanInformer.Informer().AddEventHandler(eventHandler)
+	if err != nil {
+		return fmt.Errorf("error watching %s: %s", HitmanGVR.String(), err.Error())
+	}
+
+	_, err = c.hitmanPolicyInformer.Informer().AddEventHandler(eventHandler)
+	if err != nil {
+		return fmt.Errorf("error watching %s: %s", HitmanPolicyGVR.String(), err.Error())
+	}
+
+	c.informerFactory.Start(globals.ExecContext.Context.Done())
+
+	for gvr, synced := range c.informerFactory.WaitForCacheSync(globals.ExecContext.Context.Done()) {
+		if !synced {
+			return fmt.Errorf("error syncing informer for %s. Are the CRDs installed?", gvr.String())
+		}
+	}
+
+	c.reconcile()
+	return nil
+}
+
+// objectResultT defines whether the spec of a Hitman or HitmanPolicy object could be merged into the config
+type objectResultT struct {
+	object *unstructured.Unstructured
+	err    error
+}
+
+// reconcile merges all the Hitman and HitmanPolicy objects into a config, and stores it.
+// Objects with errors are skipped and the error is reported into their status, so they never affect the rest
+func (c *Controller) reconcile() {
+	c.reconcileMutex.Lock()
+	defer c.reconcileMutex.Unlock()
+
+	configContent, objectResults, err := c.buildConfig()
+	if err == nil {
+		err = config.ApplyDefaults(configContent)
+	}
+
+	metrics.RecordConfigReload(err == nil, float64(time.Now().Unix()))
+
+	if err != nil {
+		globals.ExecContext.Logger.Infof("error building config from Kubernetes objects. Keeping previous one: %s", err.Error())
+		return
+	}
+
+	changes := config.Store(configContent)
+
+	globals.ExecContext.Logger.Infow("config built from Kubernetes objects",
+		"rules", len(configContent.Spec.Resources), "changes", changes)
+
+	c.reportObjectResults(objectResults)
+}
+
+// buildConfig merges all the Hitman and HitmanPolicy objects into a config.
+// Global settings (synchronization, Kubernetes clients, clusters, templates...) are taken from the first valid Hitman object ordered by name.
+// Each object is validated before merging it, and skipped when it is not valid
+func (c *Controller) buildConfig() (configContent *v1alpha1.ConfigT, objectResults []objectResultT, err error) {
+
+	configContent = &v1alpha1.ConfigT{
+		ApiVersion: v1alpha1.Group + "/" + v1alpha1.Version,
+		Kind:       v1alpha1.KindHitman,
+	}
+
+	hitmanObjects, err := listObjects(c.hitmanInformer)
+	if err != nil {
+		return configContent, objectResults, err
+	}
+
+	for _, hitmanObject := range hitmanObjects {
+		hitmanSpec := v1alpha1.SpecificationT{}
+
+		err = convertSpec(hitmanObject, &hitmanSpec)
+		if err != nil {
+			objectResults = append(objectResults, objectResultT{object: hitmanObject, err: err})
+			continue
+		}
+
+		candidateConfig := *configContent
+
+		if configContent.Metadata.Name == "" {
+			candidateConfig.Metadata.Name = hitmanObject.GetName()
+			candidateConfig.Spec.Synchronization = hitmanSpec.Synchronization
+			candidateConfig.Spec.Clusters = hitmanSpec.Clusters
+			candidateConfig.Spec.Kubernetes = hitmanSpec.Kubernetes
+			candidateConfig.Spec.Audit = hitmanSpec.Audit
+			candidateConfig.Spec.Notifications = hitmanSpec.Notifications
+			candidateConfig.Spec.Templates = hitmanSpec.Templates
+		}
+
+		err = mergeResources(&candidateConfig, hitmanObject, getSourcedResources(hitmanObject, hitmanSpec.Resources))
+		objectResults = append(objectResults, objectResultT{object: hitmanObject, err: err})
+		if err != nil {
+			continue
+		}
+
+		if configContent.Metadata.Name != "" && !reflect.DeepEqual(hitmanSpec.Synchronization, configContent.Spec.Synchronization) {
+			globals.ExecContext.Logger.Infof("synchronization settings of %s '%s' are ignored. Using the ones from '%s'",
+				v1alpha1.KindHitman, hitmanObject.GetName(), configContent.Metadata.Name)
+		}
+
+		if configContent.Metadata.Name != "" && len(hitmanSpec.Clusters) > 0 && !reflect.DeepEqual(hitmanSpec.Clusters, configContent.Spec.Clusters) {
+			globals.ExecContext.Logger.Infof("clusters of %s '%s' are ignored. Using the ones from '%s'",
+				v1alpha1.KindHitman, hitmanObject.GetName(), configContent.Metadata.Name)
+		}
+
+		if configContent.Metadata.Name != "" && len(hitmanSpec.Templates) > 0 && !reflect.DeepEqual(hitmanSpec.Templates, configContent.Spec.Templates) {
+			globals.ExecContext.Logger.Infof("templates of %s '%s' are ignored. Using the ones from '%s'",
+				v1alpha1.KindHitman, hitmanObject.GetName(), configContent.Metadata.Name)
+		}
+
+		*configContent = candidateConfig
+	}
+
+	hitmanPolicyObjects, err := listObjects(c.hitmanPolicyInformer)
+	if err != nil {
+		return configContent, objectResults, err
+	}
+
+	for _, hitmanPolicyObject := range hitmanPolicyObjects {
+		hitmanPolicySpec := v1alpha1.PolicySpecificationT{}
+
+		err = convertSpec(hitmanPolicyObject, &hitmanPolicySpec)
+		if err != nil {
+			objectResults = append(objectResults, objectResultT{object: hitmanPolicyObject, err: err})
+			continue
+		}
+
+		localClusterName := getLocalClusterName(configContent.Spec.Clusters)
+		if localClusterName == "" {
+			err = fmt.Errorf("the cluster where Hitman runs is not declared in the clusters of %s '%s'",
+				v1alpha1.KindHitman, configContent.Metadata.Name)
+			objectResults = append(objectResults, objectResultT{object: hitmanPolicyObject, err: err})
+			continue
+		}
+
+		policyResources := getSourcedResources(hitmanPolicyObject, hitmanPolicySpec.Resources)
+		err = confineResources(hitmanPolicyObject, hitmanPolicySpec.ServiceAccountName, localClusterName, policyResources)
+		if err != nil {
+			objectResults = append(objectResults, objectResultT{object: hitmanPolicyObject, err: err})
+			continue
+		}
+
+		err = mergeResources(configContent, hitmanPolicyObject, policyResources)
+		objectResults = append(objectResults, objectResultT{object: hitmanPolicyObject, err: err})
+	}
+
+	return configContent, objectResults, nil
+}
+
+// mergeResources appends the resources of an object to the config when they are valid.
+// They are validated alone first, so errors point to their positions inside the object,
+// and then together with the rest, to catch conflicts between objects (e.g. duplicated rule names)
+func mergeResources(configContent *v1alpha1.ConfigT, object *unstructured.Unstructured, resources []v1alpha1.ResourceT) (err error) {
+
+	// Validation fills some carried fields, so it is done over copies
+	objectConfig := *configContent
+	objectConfig.Spec.Resources = slices.Clone(resources)
+
+	err = config.ApplyDefaults(&objectConfig)
+	if err != nil {
+		return err
+	}
+
+	mergedConfig := *configContent
+	mergedConfig.Spec.Resources = append(slices.Clone(configContent.Spec.Resources), resources...)
+
+	err = config.ApplyDefaults(&mergedConfig)
+	if err != nil {
+		return fmt.Errorf("conflict with other objects: %s", err.Error())
+	}
+
+	configContent.Spec.Resources = append(configContent.Spec.Resources, resources...)
+	return nil
+}
+
+// listObjects return all the objects known by an informer ordered by namespace and name
+func listObjects(informer informers.GenericInformer) (objects []*unstructured.Unstructured, err error) {
+	rawObjects, err := informer.Lister().List(labels.Everything())
+	if err != nil {
+		return objects, err
+	}
+
+	for _, rawObject := range rawObjects {
+		object, ok := rawObject.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		objects = append(objects, object)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].GetNamespace() != objects[j].GetNamespace() {
+			return objects[i].GetNamespace() < objects[j].GetNamespace()
+		}
+		return objects[i].GetName() < objects[j].GetName()
+	})
+
+	return objects, nil
+}
+
+// convertSpec converts the spec of a Kubernetes object into the given type.
+// Config types are defined for YAML, and JSON is a subset of YAML, so they can be parsed directly
+func convertSpec(object *unstructured.Unstructured, spec interface{}) (err error) {
+	specBytes, err := json.Marshal(object.Object["spec"])
+	if err != nil {
+		return fmt.Errorf("error reading spec of %s '%s': %s", object.GetKind(), object.GetName(), err.Error())
+	}
+
+	err = yaml.Unmarshal(specBytes, spec)
+	if err != nil {
+		return fmt.Errorf("error parsing spec of %s '%s': %s", object.GetKind(), object.GetName(), err.Error())
+	}
+
+	return nil
+}
+
+// getSourcedResources return the resources tagged with the object they were read from
+func getSourcedResources(object *unstructured.Unstructured, resources []v1alpha1.ResourceT) []v1alpha1.ResourceT {
+	sourcedResources := make([]v1alpha1.ResourceT, 0, len(resources))
+
+	for resourceIndex, resource := range resources {
+		resource.CarriedSource = v1alpha1.SourceT{
+			Kind:      object.GetKind(),
+			Namespace: object.GetNamespace(),
+			Name:      object.GetName(),
+			Index:     resourceIndex,
+		}
+		sourcedResources = append(sourcedResources, resource)
+	}
+
+	return sourcedResources
+}
+
+// getLocalClusterName return the name of the cluster where Hitman runs: the declared one that uses the credentials of Hitman itself.
+// When there is no cluster declared, it is the only one. An empty name is returned when it is not declared
+func getLocalClusterName(clusters []v1alpha1.ClusterT) string {
+	if len(clusters) == 0 {
+		return v1alpha1.DefaultClusterName
+	}
+
+	for _, cluster := range clusters {
+		if cluster.Kubeconfig == "" && cluster.Context == "" && cluster.SecretRef == (v1alpha1.SecretKeyReferenceT{}) {
+			return cluster.Name
+		}
+	}
+
+	return ""
+}
+
+// confineResources restricts the resources of a namespaced policy to its namespace, in the cluster where Hitman runs.
+// They are processed impersonating a ServiceAccount of that namespace, so a tenant can never act beyond its RBAC.
+// Backups into directories are rejected, as they would be written with the permissions of Hitman itself
+func confineResources(object *unstructured.Unstructured, serviceAccountName string, localClusterName string, resources []v1alpha1.ResourceT) (err error) {
+
+	if serviceAccountName == "" {
+		serviceAccountName = v1alpha1.DefaultPolicyServiceAccountName
+	}
+
+	for resourceIndex := range resources {
+		if resources[resourceIndex].Backup.Type == v1alpha1.BackupTypeDirectory {
+			return fmt.Errorf("resources[%d]: backups of type '%s' are not allowed in %s objects",
+				resourceIndex, v1alpha1.BackupTypeDirectory, object.GetKind())
+		}
+
+		namespaceSelector := resources[resourceIndex].Target.Namespace
+
+		if namespaceSelector.MatchRegex != "" ||
+			(namespaceSelector.MatchExact != "" && namespaceSelector.MatchExact != object.GetNamespace()) {
+			globals.ExecContext.Logger.Infof("namespace selector of resources[%d] in %s '%s' is ignored. Targets are confined to namespace '%s'",
+				resourceIndex, object.GetKind(), object.GetName(), object.GetNamespace())
+		}
+
+		if len(resources[resourceIndex].Clusters) > 0 && !slices.Equal(resources[resourceIndex].Clusters, []string{localClusterName}) {
+			globals.ExecContext.Logger.Infof("clusters of resources[%d] in %s '%s' are ignored. Targets are confined to cluster '%s'",
+				resourceIndex, object.GetKind(), object.GetName(), localClusterName)
+		}
+
+		// Tenants can not arm their rules when the whole controller runs in dry-run mode
+		if globals.ExecContext.DryRun && resources[resourceIndex].DryRun != nil && !*resources[resourceIndex].DryRun {
+			globals.ExecContext.Logger.Infof("dryRun of resources[%d] in %s '%s' is ignored. Hitman runs with --dry-run",
+				resourceIndex, object.GetKind(), object.GetName())
+			resources[resourceIndex].DryRun = nil
+		}
+
+		if rules.IsImpersonating(resources[resourceIndex]) {
+			globals.ExecContext.Logger.Infof("impersonation settings of resources[%d] in %s '%s' are ignored. ServiceAccount '%s' is impersonated",
+				resourceIndex, object.GetKind(), object.GetName(), serviceAccountName)
+		}
+
+		resources[resourceIndex].Target.Namespace = v1alpha1.TargetSelectorT{
+			MatchExact: object.GetNamespace(),
+		}
+		resources[resourceIndex].Clusters = []string{localClusterName}
+
+		// Backups stored in the cluster are kept inside the namespace too
+		if resources[resourceIndex].Backup.Namespace != "" && resources[resourceIndex].Backup.Namespace != object.GetNamespace() {
+			globals.ExecContext.Logger.Infof("backup namespace of resources[%d] in %s '%s' is ignored. Backups are confined to namespace '%s'",
+				resourceIndex, object.GetKind(), object.GetName(), object.GetNamespace())
+		}
+
+		if resources[resourceIndex].Backup.Type == v1alpha1.BackupTypeConfigMap ||
+			resources[resourceIndex].Backup.Type == v1alpha1.BackupTypeSecret {
+			resources[resourceIndex].Backup.Namespace = object.GetNamespace()
+		}
+
+		resources[resourceIndex].Impersonate = v1alpha1.ImpersonateT{
+			ServiceAccount: v1alpha1.ServiceAccountReferenceT{
+				Namespace: object.GetNamespace(),
+				Name:      serviceAccountName,
+			},
+		}
+	}
+
+	return nil
+}
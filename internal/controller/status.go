@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	//
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+	"hitman/internal/rules"
+)
+
+// ReportStatus writes the results of the last loop into the status of the objects the rules were read from.
+// Statuses are given per cluster, indexed by the name of the rule they belong to
+func (c *Controller) ReportStatus(resources []v1alpha1.ResourceT, clusterRuleStatuses ...map[string]*v1alpha1.RuleStatusT) {
+
+	// Group the statuses by the object their rules were read from
+	objectStatuses := map[v1alpha1.SourceT]*v1alpha1.StatusT{}
+	var objectSources []v1alpha1.SourceT
+
+	for resourceIndex, resource := range resources {
+		source := resource.CarriedSource
+		if source.Kind == "" {
+			continue
+		}
+
+		// Rules of the same object share the key
+		objectSource := source
+		objectSource.Index = 0
+
+		ruleName := rules.GetName(resourceIndex, resource)
+
+		for _, ruleStatuses := range clusterRuleStatuses {
+			processedRuleStatus, found := ruleStatuses[ruleName]
+
+			// Rule was not processed in this cluster
+			if !found {
+				continue
+			}
+
+			// Objects only know the names of their rules, without the object they come from
+			ruleStatus := *processedRuleStatus
+			ruleStatus.Name = resource.Name
+			if ruleStatus.Name == "" {
+				ruleStatus.Name = fmt.Sprintf("resources[%d]", source.Index)
+			}
+
+			objectStatus, found := objectStatuses[objectSource]
+			if !found {
+				objectStatus = &v1alpha1.StatusT{
+					LastRun: ruleStatus.LastRun,
+				}
+				objectStatuses[objectSource] = objectStatus
+				objectSources = append(objectSources, objectSource)
+			}
+
+			objectStatus.Rules = append(objectStatus.Rules, ruleStatus)
+		}
+	}
+
+	for _, source := range objectSources {
+		err := c.patchStatus(source, objectStatuses[source])
+		if err != nil {
+			globals.ExecContext.Logger.Infof("error reporting status of %s '%s' in namespace '%s': %s",
+				source.Kind, source.Name, source.Namespace, err.Error())
+		}
+	}
+}
+
+// reportObjectResults writes into the status of the objects whether their spec could be used.
+// Objects with errors get the error, and their previous results are removed as their rules are not processed anymore.
+// Errors are cleared from the objects that were fixed
+func (c *Controller) reportObjectResults(objectResults []objectResultT) {
+
+	for _, objectResult := range objectResults {
+		source := v1alpha1.SourceT{
+			Kind:      objectResult.object.GetKind(),
+			Namespace: objectResult.object.GetNamespace(),
+			Name:      objectResult.object.GetName(),
+		}
+
+		// Null values remove the fields with a merge patch
+		var status map[string]interface{}
+
+		previousError, _, _ := unstructured.NestedString(objectResult.object.Object, "status", "error")
+
+		switch {
+		case objectResult.err != nil:
+			globals.ExecContext.Logger.Infof("error in %s '%s' in namespace '%s'. Its rules are ignored: %s",
+				source.Kind, source.Name, source.Namespace, objectResult.err.Error())
+
+			if previousError == objectResult.err.Error() {
+				continue
+			}
+			status = map[string]interface{}{"error": objectResult.err.Error(), "lastRun": nil, "rules": nil}
+
+		case previousError != "":
+			status = map[string]interface{}{"error": nil}
+
+		default:
+			continue
+		}
+
+		err := c.patchStatus(source, status)
+		if err != nil {
+			globals.ExecContext.Logger.Infof("error reporting status of %s '%s' in namespace '%s': %s",
+				source.Kind, source.Name, source.Namespace, err.Error())
+		}
+	}
+}
+
+// patchStatus merges the given status into the one of an object through its status subresource
+func (c *Controller) patchStatus(source v1alpha1.SourceT, status interface{}) (err error) {
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"status": status,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resourceInterface dynamic.ResourceInterface = c.Client.Resource(HitmanGVR)
+	if source.Kind == v1alpha1.KindHitmanPolicy {
+		resourceInterface = c.Client.Resource(HitmanPolicyGVR).Namespace(source.Namespace)
+	}
+
+	_, err = resourceInterface.Patch(globals.ExecContext.Context, source.Name, types.MergePatchType,
+		patchBytes, v1.PatchOptions{}, "status")
+
+	// Object could be deleted while the loop was running
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
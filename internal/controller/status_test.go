@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	//
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+func TestReportStatus(t *testing.T) {
+	var patchesMutex sync.Mutex
+	patches := map[string]v1alpha1.StatusT{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		patch := struct {
+			Status v1alpha1.StatusT `json:"status"`
+		}{}
+		_ = json.Unmarshal(body, &patch)
+
+		patchesMutex.Lock()
+		patches[request.URL.Path] = patch.Status
+		patchesMutex.Unlock()
+
+		response.Header().Set("Content-Type", "application/json")
+		_, _ = response.Write([]byte(`{"apiVersion":"hitman.io/v1alpha1","kind":"HitmanPolicy","metadata":{"name":"policy"}}`))
+	}))
+	defer server.Close()
+
+	client, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+
+	source := v1alpha1.SourceT{Kind: v1alpha1.KindHitmanPolicy, Namespace: "team-a", Name: "policy"}
+	firstSource, secondSource := source, source
+	secondSource.Index = 1
+
+	// Rules were swapped after the loop, so their positions do not match the ones the statuses were taken with
+	resources := []v1alpha1.ResourceT{
+		{Name: "second", CarriedSource: firstSource},
+		{Name: "first", CarriedSource: secondSource},
+		{Name: "new", CarriedSource: secondSource},
+	}
+
+	ruleStatuses := map[string]*v1alpha1.RuleStatusT{
+		"HitmanPolicy/team-a/policy/first":  {Name: "HitmanPolicy/team-a/policy/first", Killed: 1},
+		"HitmanPolicy/team-a/policy/second": {Name: "HitmanPolicy/team-a/policy/second", Killed: 2},
+	}
+
+	controller := &Controller{Client: client}
+	controller.ReportStatus(resources, ruleStatuses)
+
+	status, found := patches["/apis/hitman.io/v1alpha1/namespaces/team-a/hitmanpolicies/policy/status"]
+	if !found {
+		t.Fatalf("got patches %v, want the status of the policy", patches)
+	}
+
+	wantKilled := map[string]int{"second": 2, "first": 1}
+	if len(status.Rules) != len(wantKilled) {
+		t.Fatalf("got rules %v, want %v", status.Rules, wantKilled)
+	}
+
+	for _, ruleStatus := range status.Rules {
+		if killed, expected := wantKilled[ruleStatus.Name]; !expected || killed != ruleStatus.Killed {
+			t.Fatalf("got rule '%s' with %d killed objects, want %v", ruleStatus.Name, ruleStatus.Killed, wantKilled)
+		}
+	}
+
+	// Statuses of the processor are not modified when they are reported
+	if ruleStatuses["HitmanPolicy/team-a/policy/first"].Name != "HitmanPolicy/team-a/policy/first" {
+		t.Fatalf("got status renamed to '%s' in the processor", ruleStatuses["HitmanPolicy/team-a/policy/first"].Name)
+	}
+}
@@ -5,13 +5,20 @@ package globals
 
 import (
 	"context"
+	"fmt"
 	"hitman/api/v1alpha1"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+const (
+	LogFormatJson    = "json"
+	LogFormatConsole = "console"
+)
+
 var (
 	ExecContext = ExecutionContext{
 		Context: context.Background(),
@@ -22,20 +29,34 @@ var (
 type ExecutionContext struct {
 	Context context.Context
 	Logger  zap.SugaredLogger
-	Config  v1alpha1.ConfigT
+
+	// Config in use. It is replaced as a whole on reloads, so readers must load it once and keep
+	// the snapshot while working. Stored configs are never modified
+	Config atomic.Pointer[v1alpha1.ConfigT]
 
 	//
 	LogLevel string
 	DryRun   bool
+
+	// Kubernetes client settings defined by flags. They take precedence over the ones in the config
+	KubernetesClientFlags v1alpha1.KubernetesClientT
+
+	// Environment variables and files that can be expanded in config files, as glob patterns
+	ConfigEnvAllowlist  []string
+	ConfigFileAllowlist []string
 }
 
 // SetLogger TODO
-func SetLogger(logLevel string, disableTrace bool) (err error) {
+func SetLogger(logLevel string, logFormat string, disableTrace bool) (err error) {
 	parsedLogLevel, err := zap.ParseAtomicLevel(logLevel)
 	if err != nil {
 		return err
 	}
 
+	if logFormat != LogFormatJson && logFormat != LogFormatConsole {
+		return fmt.Errorf("log format must be '%s' or '%s'", LogFormatJson, LogFormatConsole)
+	}
+
 	// Initialize the logger
 	loggerConfig := zap.NewProductionConfig()
 	if disableTrace {
@@ -47,6 +68,12 @@ func SetLogger(logLevel string, disableTrace bool) (err error) {
 	loggerConfig.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(time.RFC3339)
 	loggerConfig.Level.SetLevel(parsedLogLevel.Level())
 
+	// Console format is easier to read by humans. Levels are colored too
+	loggerConfig.Encoding = logFormat
+	if logFormat == LogFormatConsole {
+		loggerConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
 	// Configure the logger
 	logger, err := loggerConfig.Build()
 	if err != nil {
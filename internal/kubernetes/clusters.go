@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"fmt"
+	"reflect"
+
+	//
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+const (
+	// DefaultKubeconfigSecretKey is the key of a Secret where the kubeconfig is read from when not specified
+	DefaultKubeconfigSecretKey = "kubeconfig"
+)
+
+// GetClusterConfig return the config to connect to a cluster declared in the config.
+// Kubeconfig files and Secrets can contain several contexts, so the desired one can be selected.
+// Given settings tune the clients of the cluster
+func GetClusterConfig(cluster v1alpha1.ClusterT, settings v1alpha1.KubernetesClientT) (config *rest.Config, err error) {
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: cluster.Context}
+
+	switch {
+	case cluster.Kubeconfig != "" && !reflect.ValueOf(cluster.SecretRef).IsZero():
+		return config, fmt.Errorf("cluster '%s' can only declare one of kubeconfig or secretRef", cluster.Name)
+
+	case cluster.Kubeconfig != "":
+		loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: cluster.Kubeconfig}
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+
+	case !reflect.ValueOf(cluster.SecretRef).IsZero():
+		kubeconfigBytes, err := getSecretKubeconfig(cluster.SecretRef, settings)
+		if err != nil {
+			return config, fmt.Errorf("error reading kubeconfig of cluster '%s': %s", cluster.Name, err.Error())
+		}
+
+		clientConfig, err := clientcmd.Load(kubeconfigBytes)
+		if err != nil {
+			return config, fmt.Errorf("error parsing kubeconfig of cluster '%s': %s", cluster.Name, err.Error())
+		}
+
+		config, err = clientcmd.NewNonInteractiveClientConfig(*clientConfig, cluster.Context, overrides, nil).ClientConfig()
+
+	// Cluster where Hitman runs, maybe with a different context
+	default:
+		if cluster.Context != "" {
+			settings.Context = cluster.Context
+		}
+		return GetConfig(settings)
+	}
+
+	if err != nil {
+		return config, err
+	}
+
+	ApplyClientSettings(config, settings)
+	return config, nil
+}
+
+// getSecretKubeconfig return the kubeconfig stored in a Secret of the cluster where Hitman runs
+func getSecretKubeconfig(secretRef v1alpha1.SecretKeyReferenceT, settings v1alpha1.KubernetesClientT) (kubeconfigBytes []byte, err error) {
+
+	if secretRef.Namespace == "" || secretRef.Name == "" {
+		return kubeconfigBytes, fmt.Errorf("secretRef requires namespace and name")
+	}
+
+	key := secretRef.Key
+	if key == "" {
+		key = DefaultKubeconfigSecretKey
+	}
+
+	config, err := GetConfig(settings)
+	if err != nil {
+		return kubeconfigBytes, err
+	}
+
+	client, err := NewClientset(config)
+	if err != nil {
+		return kubeconfigBytes, err
+	}
+
+	secret, err := client.CoreV1().Secrets(secretRef.Namespace).Get(globals.ExecContext.Context, secretRef.Name, v1.GetOptions{})
+	if err != nil {
+		return kubeconfigBytes, err
+	}
+
+	kubeconfigBytes, found := secret.Data[key]
+	if !found {
+		return kubeconfigBytes, fmt.Errorf("key '%s' not found in Secret '%s/%s'", key, secretRef.Namespace, secretRef.Name)
+	}
+
+	return kubeconfigBytes, nil
+}
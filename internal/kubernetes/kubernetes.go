@@ -4,22 +4,103 @@
 package kubernetes
 
 import (
+	"fmt"
+	"strings"
+
 	// Kubernetes clients
 	// Ref: https://pkg.go.dev/k8s.io/client-go/dynamic
 	dynamic "k8s.io/client-go/dynamic"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	// Ref: https://pkg.go.dev/k8s.io/client-go/restmapper
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+
+	"k8s.io/client-go/tools/clientcmd"
 
 	// Ref: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/client/config
-	ctrl "sigs.k8s.io/controller-runtime"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+const (
+	DefaultQPS   = 20.0
+	DefaultBurst = 30
+
+	// Prefix of the usernames of ServiceAccounts, followed by '<namespace>:<name>'
+	serviceAccountUserNamePrefix = "system:serviceaccount:"
 )
 
+// GetConfig return the config to connect to the cluster where Hitman runs.
+// When no kubeconfig is given, it is discovered as controller-runtime does: KUBECONFIG, in-cluster, $HOME/.kube/config
+func GetConfig(settings v1alpha1.KubernetesClientT) (config *rest.Config, err error) {
+
+	switch settings.Kubeconfig {
+	case "":
+		config, err = ctrl.GetConfigWithContext(settings.Context)
+	default:
+		loadingRules := &clientcmd.ClientConfigLoadingRules{ExplicitPath: settings.Kubeconfig}
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: settings.Context}
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	}
+
+	if err != nil {
+		return config, err
+	}
+
+	ApplyClientSettings(config, settings)
+	return config, nil
+}
+
+// ApplyClientSettings tunes the clients created from a config. Settings not defined are kept untouched
+func ApplyClientSettings(config *rest.Config, settings v1alpha1.KubernetesClientT) {
+
+	// Same defaults as controller-runtime, which are based on the Kubernetes controller manager ones
+	config.QPS = max(config.QPS, DefaultQPS)
+	config.Burst = max(config.Burst, DefaultBurst)
+
+	if settings.QPS > 0 {
+		config.QPS = settings.QPS
+	}
+
+	if settings.Burst > 0 {
+		config.Burst = settings.Burst
+	}
+
+	if settings.CarriedTimeout > 0 {
+		config.Timeout = settings.CarriedTimeout
+	}
+
+	if settings.UserAgent != "" {
+		config.UserAgent = settings.UserAgent
+	}
+}
+
 // NewClient return a new Kubernetes client from client-go SDK
-func NewClient() (client *dynamic.DynamicClient, err error) {
-	config, err := ctrl.GetConfig()
+func NewClient(config *rest.Config) (client *dynamic.DynamicClient, err error) {
+
+	// Create the clients to do requests to our friend: Kubernetes
+	client, err = dynamic.NewForConfig(config)
 	if err != nil {
 		return client, err
 	}
 
-	// Create the clients to do requests to our friend: Kubernetes
+	return client, err
+}
+
+// NewImpersonatingClient return a new Kubernetes client from client-go SDK that acts as the given identity.
+// Requests are authorized by Kubernetes for that identity, so its RBAC is enforced
+// Ref: https://kubernetes.io/docs/reference/access-authn-authz/authentication/#user-impersonation
+func NewImpersonatingClient(config *rest.Config, impersonationConfig rest.ImpersonationConfig) (client *dynamic.DynamicClient, err error) {
+
+	// Given config is shared by other clients. Don't touch it
+	config = rest.CopyConfig(config)
+	config.Impersonate = impersonationConfig
+
 	client, err = dynamic.NewForConfig(config)
 	if err != nil {
 		return client, err
@@ -27,3 +108,47 @@ func NewClient() (client *dynamic.DynamicClient, err error) {
 
 	return client, err
 }
+
+// GetServiceAccountImpersonationConfig return the config needed to impersonate a ServiceAccount
+func GetServiceAccountImpersonationConfig(namespace, name string) rest.ImpersonationConfig {
+	return rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("%s%s:%s", serviceAccountUserNamePrefix, namespace, name),
+	}
+}
+
+// ParseServiceAccountUserName return the namespace and name of the ServiceAccount a username belongs to.
+// It returns false when the username is not the one of a ServiceAccount
+func ParseServiceAccountUserName(userName string) (namespace, name string, ok bool) {
+	serviceAccount, found := strings.CutPrefix(userName, serviceAccountUserNamePrefix)
+	if !found {
+		return namespace, name, false
+	}
+
+	namespace, name, found = strings.Cut(serviceAccount, ":")
+	return namespace, name, found && namespace != "" && name != ""
+}
+
+// NewRESTMapper return a mapper able to translate Kubernetes kinds into resources.
+// Discovery responses are cached in memory, so the mapper must be reset to discover new APIs
+func NewRESTMapper(config *rest.Config) (mapper *restmapper.DeferredDiscoveryRESTMapper, err error) {
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return mapper, err
+	}
+
+	mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return mapper, err
+}
+
+// NewClientset return a new typed Kubernetes client from client-go SDK.
+// It is only used for those requests that are not about resources, such as access reviews
+func NewClientset(config *rest.Config) (client *clientset.Clientset, err error) {
+
+	client, err = clientset.NewForConfig(config)
+	if err != nil {
+		return client, err
+	}
+
+	return client, err
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+
+	//
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+const (
+	namespace = "hitman"
+
+	labelCluster = "cluster"
+	labelRule    = "rule"
+	labelResult  = "result"
+
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	ruleMatchedObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_matched_objects_total",
+		Help:      "Objects whose conditions were met",
+	}, []string{labelCluster, labelRule})
+
+	ruleKilledObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_killed_objects_total",
+		Help:      "Objects deleted",
+	}, []string{labelCluster, labelRule})
+
+	ruleCondemnedObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_condemned_objects_total",
+		Help:      "Objects condemned to be killed after a grace period",
+	}, []string{labelCluster, labelRule})
+
+	ruleWouldKillObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_would_kill_objects_total",
+		Help:      "Objects that would have been killed by rules in dry-run mode",
+	}, []string{labelCluster, labelRule})
+
+	ruleWouldCondemnObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_would_condemn_objects_total",
+		Help:      "Objects that would have been condemned by rules in dry-run mode",
+	}, []string{labelCluster, labelRule})
+
+	ruleDryRun = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rule_dry_run",
+		Help:      "Whether rules are in dry-run mode (1) or actually killing objects (0)",
+	}, []string{labelCluster, labelRule})
+
+	ruleErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rule_errors_total",
+		Help:      "Errors found processing rules",
+	}, []string{labelCluster, labelRule})
+
+	ruleLastRun = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "rule_last_run_timestamp_seconds",
+		Help:      "Time when rules were processed for the last time",
+	}, []string{labelCluster, labelRule})
+
+	configReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "config_reloads_total",
+		Help:      "Attempts to reload the config, by result",
+	}, []string{labelResult})
+
+	configLastReloadSuccessful = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "config_last_reload_successful",
+		Help:      "Whether the last attempt to reload the config succeeded (1) or failed (0)",
+	})
+
+	configLastReloadSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "config_last_reload_success_timestamp_seconds",
+		Help:      "Time when the config was reloaded successfully for the last time",
+	})
+)
+
+func init() {
+	registry.MustRegister(ruleMatchedObjects, ruleKilledObjects, ruleCondemnedObjects, ruleWouldKillObjects, ruleWouldCondemnObjects, ruleDryRun,
+		ruleErrors, ruleLastRun, configReloads, configLastReloadSuccessful, configLastReloadSuccess)
+}
+
+// RecordRuleStatus adds the results of a rule processed in a cluster to the metrics
+func RecordRuleStatus(ruleStatus v1alpha1.RuleStatusT, lastRunTimestamp float64) {
+	labels := prometheus.Labels{labelCluster: ruleStatus.Cluster, labelRule: ruleStatus.Name}
+
+	ruleMatchedObjects.With(labels).Add(float64(ruleStatus.Matched))
+	ruleKilledObjects.With(labels).Add(float64(ruleStatus.Killed))
+	ruleCondemnedObjects.With(labels).Add(float64(ruleStatus.Condemned))
+	ruleWouldKillObjects.With(labels).Add(float64(ruleStatus.WouldKill))
+	ruleWouldCondemnObjects.With(labels).Add(float64(ruleStatus.WouldCondemn))
+	ruleErrors.With(labels).Add(float64(ruleStatus.Errors))
+	ruleLastRun.With(labels).Set(lastRunTimestamp)
+
+	dryRun := 0.0
+	if ruleStatus.DryRun {
+		dryRun = 1
+	}
+	ruleDryRun.With(labels).Set(dryRun)
+}
+
+// RecordConfigReload adds an attempt to reload the config to the metrics
+func RecordConfigReload(success bool, timestamp float64) {
+	if !success {
+		configReloads.With(prometheus.Labels{labelResult: resultFailure}).Inc()
+		configLastReloadSuccessful.Set(0)
+		return
+	}
+
+	configReloads.With(prometheus.Labels{labelResult: resultSuccess}).Inc()
+	configLastReloadSuccessful.Set(1)
+	configLastReloadSuccess.Set(timestamp)
+}
+
+// Serve exposes the metrics on given address in Prometheus format. It blocks until the server fails
+func Serve(bindAddress string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	err := http.ListenAndServe(bindAddress, mux)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		globals.ExecContext.Logger.Infof("error serving metrics: %s", err.Error())
+	}
+}
@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/audit"
+	"hitman/internal/globals"
+)
+
+var (
+	// Notifier used by the whole application. It is rebuilt when the notifications settings change
+	defaultNotifier      = &Notifier{}
+	defaultNotifierMutex sync.Mutex
+)
+
+// requestT defines an HTTP request to be sent to a receiver
+type requestT struct {
+	url     string
+	headers map[string]string
+	body    []byte
+}
+
+// receiver is a place where notifications are sent
+type receiver interface {
+	getName() string
+	getRequest(events []audit.EventT) (requestT, error)
+}
+
+// Notifier batches the events of a loop, and sends them to all the receivers defined in the config
+type Notifier struct {
+	config    v1alpha1.NotificationsT
+	receivers []receiver
+
+	client       *http.Client
+	retries      int
+	retryBackoff time.Duration
+
+	events      []audit.EventT
+	eventsMutex sync.Mutex
+}
+
+// NewNotifier return a notifier sending to the receivers defined in the config.
+// Receivers that can not be created are returned as errors, and the rest of them are still used
+func NewNotifier(config v1alpha1.NotificationsT) (notifier *Notifier, errs []error) {
+	notifier = &Notifier{
+		config:  config,
+		retries: config.Retries,
+	}
+
+	if notifier.retries <= 0 {
+		notifier.retries = v1alpha1.DefaultNotificationsRetries
+	}
+
+	if config.RetryBackoff == "" {
+		config.RetryBackoff = v1alpha1.DefaultNotificationsRetryBackoff
+	}
+
+	if config.Timeout == "" {
+		config.Timeout = v1alpha1.DefaultNotificationsTimeout
+	}
+
+	var err error
+	notifier.retryBackoff, err = time.ParseDuration(config.RetryBackoff)
+	if err != nil {
+		return notifier, []error{fmt.Errorf("unable to parse duration: %s", err.Error())}
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return notifier, []error{fmt.Errorf("unable to parse duration: %s", err.Error())}
+	}
+	notifier.client = &http.Client{Timeout: timeout}
+
+	for receiverIndex, receiverConfig := range config.Receivers {
+		receiverObj, err := newReceiver(receiverIndex, receiverConfig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error creating notification receivers[%d]: %s", receiverIndex, err.Error()))
+			continue
+		}
+		notifier.receivers = append(notifier.receivers, receiverObj)
+	}
+
+	return notifier, errs
+}
+
+// Enabled return true when there is, at least, one receiver to notify
+func (n *Notifier) Enabled() bool {
+	return len(n.receivers) > 0
+}
+
+// Add stores an event to be notified on next flush
+func (n *Notifier) Add(event audit.EventT) {
+	n.eventsMutex.Lock()
+	defer n.eventsMutex.Unlock()
+
+	// Snapshots are too big for notifications
+	event.Object = nil
+	n.events = append(n.events, event)
+}
+
+// Flush sends all the stored events to the receivers in the background, and forgets them
+func (n *Notifier) Flush() {
+	n.eventsMutex.Lock()
+	events := n.events
+	n.events = nil
+	n.eventsMutex.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	for _, receiverObj := range n.receivers {
+		go func(receiverObj receiver) {
+			err := n.notify(receiverObj, events)
+			if err != nil {
+				globals.ExecContext.Logger.Infow("error sending notification",
+					"receiver", receiverObj.getName(), "events", len(events), "error", err.Error())
+			}
+		}(receiverObj)
+	}
+}
+
+// notify sends the events to a receiver. Failed requests are retried with exponential backoff
+func (n *Notifier) notify(receiverObj receiver, events []audit.EventT) (err error) {
+
+	request, err := receiverObj.getRequest(events)
+	if err != nil {
+		return err
+	}
+
+	backoff := n.retryBackoff
+	for attempt := 0; attempt <= n.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		var retryable bool
+		retryable, err = n.send(request)
+		if err == nil || !retryable {
+			return err
+		}
+	}
+
+	return fmt.Errorf("giving up after %d retries: %s", n.retries, err.Error())
+}
+
+// send performs a request. Errors are retryable unless the receiver rejected the request itself
+func (n *Notifier) send(request requestT) (retryable bool, err error) {
+
+	httpRequest, err := http.NewRequestWithContext(globals.ExecContext.Context, http.MethodPost, request.url, bytes.NewReader(request.body))
+	if err != nil {
+		return false, err
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/json")
+	for headerName, headerValue := range request.headers {
+		httpRequest.Header.Set(headerName, headerValue)
+	}
+
+	response, err := n.client.Do(httpRequest)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode <= 299 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("receiver responded with status code %d", response.StatusCode)
+	return response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests, err
+}
+
+// Configure rebuilds the notifier used by the whole application when the notifications settings change
+func Configure(config v1alpha1.NotificationsT) {
+	defaultNotifierMutex.Lock()
+	defer defaultNotifierMutex.Unlock()
+
+	if reflect.DeepEqual(defaultNotifier.config, config) {
+		return
+	}
+
+	notifier, errs := NewNotifier(config)
+	for _, err := range errs {
+		globals.ExecContext.Logger.Info(err.Error())
+	}
+
+	defaultNotifier = notifier
+}
+
+// Enabled return true when the notifier used by the whole application has, at least, one receiver to notify
+func Enabled() bool {
+	defaultNotifierMutex.Lock()
+	defer defaultNotifierMutex.Unlock()
+
+	return defaultNotifier.Enabled()
+}
+
+// Add stores an event to be notified by the notifier of the whole application
+func Add(event audit.EventT) {
+	defaultNotifierMutex.Lock()
+	defer defaultNotifierMutex.Unlock()
+
+	defaultNotifier.Add(event)
+}
+
+// Flush sends all the events stored in the notifier of the whole application
+func Flush() {
+	defaultNotifierMutex.Lock()
+	defer defaultNotifierMutex.Unlock()
+
+	defaultNotifier.Flush()
+}
@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/audit"
+	"hitman/internal/template"
+)
+
+const (
+	// Name of the alerts fired into Alertmanager
+	alertName = "HitmanObjectKilled"
+
+	// Text sent to Slack when no template is defined
+	defaultSlackText = `{{- printf "Hitman acted on %d objects:" (len .events) -}}
+{{- range .events }}
+• {{ .kind }} {{ with .namespace }}{{ . }}/{{ end }}{{ .name }} ({{ .outcome }}) by rule '{{ .rule }}' in cluster '{{ .cluster }}'
+{{- end -}}`
+)
+
+func newReceiver(receiverIndex int, config v1alpha1.NotificationReceiverT) (receiverObj receiver, err error) {
+	name := config.Name
+	if name == "" {
+		name = fmt.Sprintf("receivers[%d]", receiverIndex)
+	}
+
+	switch config.Type {
+	case v1alpha1.NotificationReceiverTypeWebhook:
+		if config.Webhook.URL == "" {
+			return receiverObj, fmt.Errorf("webhook url is missing")
+		}
+		return &webhookReceiver{name: name, config: config.Webhook}, nil
+
+	case v1alpha1.NotificationReceiverTypeSlack:
+		if config.Slack.URL == "" {
+			return receiverObj, fmt.Errorf("slack url is missing")
+		}
+		return &slackReceiver{name: name, config: config.Slack}, nil
+
+	case v1alpha1.NotificationReceiverTypeAlertmanager:
+		if config.Alertmanager.URL == "" {
+			return receiverObj, fmt.Errorf("alertmanager url is missing")
+		}
+		return &alertmanagerReceiver{name: name, config: config.Alertmanager}, nil
+	}
+
+	return receiverObj, fmt.Errorf("type must be '%s', '%s' or '%s'", v1alpha1.NotificationReceiverTypeWebhook,
+		v1alpha1.NotificationReceiverTypeSlack, v1alpha1.NotificationReceiverTypeAlertmanager)
+}
+
+// evaluateEventsTemplate evaluates a template with the events available as .events.
+// Events are converted into maps, so their fields are accessed as they are named in JSON
+func evaluateEventsTemplate(templateString string, events []audit.EventT) (result string, err error) {
+	eventsBytes, err := json.Marshal(events)
+	if err != nil {
+		return result, err
+	}
+
+	var injectedEvents []interface{}
+	err = json.Unmarshal(eventsBytes, &injectedEvents)
+	if err != nil {
+		return result, err
+	}
+
+	templateInjectedData := map[string]interface{}{
+		"events": injectedEvents,
+	}
+
+	return template.EvaluateTemplate(templateString, &templateInjectedData)
+}
+
+// webhookReceiver sends the events to a generic HTTP endpoint.
+// Body is built from a template. When it is not defined, the events are sent as JSON
+type webhookReceiver struct {
+	name   string
+	config v1alpha1.NotificationWebhookT
+}
+
+func (r *webhookReceiver) getName() string {
+	return r.name
+}
+
+func (r *webhookReceiver) getRequest(events []audit.EventT) (request requestT, err error) {
+	request = requestT{url: r.config.URL, headers: r.config.Headers}
+
+	if r.config.Body == "" {
+		request.body, err = json.Marshal(map[string]interface{}{"events": events})
+		return request, err
+	}
+
+	body, err := evaluateEventsTemplate(r.config.Body, events)
+	if err != nil {
+		return request, fmt.Errorf("error evaluating body template: %s", err.Error())
+	}
+
+	request.body = []byte(body)
+	return request, nil
+}
+
+// slackReceiver sends a summary of the events to a Slack-compatible incoming webhook
+// Ref: https://api.slack.com/messaging/webhooks
+type slackReceiver struct {
+	name   string
+	config v1alpha1.NotificationSlackT
+}
+
+func (r *slackReceiver) getName() string {
+	return r.name
+}
+
+func (r *slackReceiver) getRequest(events []audit.EventT) (request requestT, err error) {
+	textTemplate := r.config.Text
+	if textTemplate == "" {
+		textTemplate = defaultSlackText
+	}
+
+	text, err := evaluateEventsTemplate(textTemplate, events)
+	if err != nil {
+		return request, fmt.Errorf("error evaluating text template: %s", err.Error())
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text})
+	return requestT{url: r.config.URL, body: body}, err
+}
+
+// alertmanagerReceiver fires one alert per event into Alertmanager.
+// Alerts are resolved by Alertmanager itself after its resolve timeout
+// Ref: https://prometheus.io/docs/alerting/latest/clients/
+type alertmanagerReceiver struct {
+	name   string
+	config v1alpha1.NotificationAlertmanagerT
+}
+
+func (r *alertmanagerReceiver) getName() string {
+	return r.name
+}
+
+func (r *alertmanagerReceiver) getRequest(events []audit.EventT) (request requestT, err error) {
+	var alerts []map[string]interface{}
+
+	for _, event := range events {
+		labels := map[string]string{}
+		for labelName, labelValue := range r.config.Labels {
+			labels[labelName] = labelValue
+		}
+
+		// Labels of the rule help routing alerts, but they never replace the ones set by Hitman
+		for labelName, labelValue := range event.RuleLabels {
+			labels[labelName] = labelValue
+		}
+
+		labels["alertname"] = alertName
+		labels["cluster"] = event.Cluster
+		labels["rule"] = event.Rule
+		labels["kind"] = event.Kind
+		labels["namespace"] = event.Namespace
+		labels["name"] = event.Name
+		labels["outcome"] = event.Outcome
+
+		startsAt := event.Timestamp
+		if startsAt == "" {
+			startsAt = time.Now().UTC().Format(time.RFC3339)
+		}
+
+		alerts = append(alerts, map[string]interface{}{
+			"labels": labels,
+			"annotations": map[string]string{
+				"summary": fmt.Sprintf("Hitman acted on %s %s (%s)",
+					event.Kind, strings.TrimPrefix(event.Namespace+"/"+event.Name, "/"), event.Outcome),
+			},
+			"startsAt": startsAt,
+		})
+	}
+
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return request, err
+	}
+
+	return requestT{
+		url:     strings.TrimSuffix(r.config.URL, "/") + "/api/v2/alerts",
+		headers: r.config.Headers,
+		body:    body,
+	}, nil
+}
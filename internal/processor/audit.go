@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"time"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	//
+	"hitman/internal/audit"
+	"hitman/internal/notifications"
+)
+
+// recordEvent completes an event with the object an action was performed over.
+// Then it is audited, and stored to be notified at the end of the loop.
+// Skipped objects are not recorded, as no action was performed over them
+func recordEvent(event audit.EventT, object unstructured.Unstructured, outcome string, err error) {
+	auditEnabled := audit.Enabled()
+	notificationsEnabled := notifications.Enabled()
+
+	if outcome == outcomeSkipped || (!auditEnabled && !notificationsEnabled) {
+		return
+	}
+
+	// Real time is recorded, even when the clock used by templates is frozen
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	event.APIVersion = object.GetAPIVersion()
+	event.Kind = object.GetKind()
+	event.Namespace = object.GetNamespace()
+	event.Name = object.GetName()
+	event.UID = string(object.GetUID())
+
+	event.Action = actionDelete
+	if outcome == outcomeCondemned || outcome == outcomeWouldCondemn {
+		event.Action = actionCondemn
+	}
+
+	if outcome == outcomeWouldCondemn {
+		outcome = outcomeDryRun
+	}
+	event.Outcome = outcome
+	event.Object = object.Object
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	if auditEnabled {
+		audit.Record(event)
+	}
+
+	if notificationsEnabled {
+		notifications.Add(event)
+	}
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"strings"
+
+	//
+	"k8s.io/client-go/dynamic"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/kubernetes"
+	"hitman/internal/rules"
+)
+
+// ruleClient groups the Kubernetes client used to process a rule and the identity it acts as.
+// The identity is empty when the client acts as Hitman itself
+type ruleClient struct {
+	client   *dynamic.DynamicClient
+	identity string
+}
+
+// getRuleClient return the Kubernetes client used to process a rule.
+// Rules declaring an identity to impersonate get a dedicated client, which is cached for later loops
+func (p *Processor) getRuleClient(configResource v1alpha1.ResourceT) (result ruleClient, err error) {
+
+	if !rules.IsImpersonating(configResource) {
+		return ruleClient{client: p.Client}, nil
+	}
+
+	impersonationConfig, err := rules.GetImpersonationConfig(configResource.Impersonate)
+	if err != nil {
+		return result, err
+	}
+
+	// Same user with different groups can have different permissions
+	identity := impersonationConfig.UserName
+	if len(impersonationConfig.Groups) > 0 {
+		identity += "[" + strings.Join(impersonationConfig.Groups, ",") + "]"
+	}
+
+	p.impersonatingClientsMutex.Lock()
+	defer p.impersonatingClientsMutex.Unlock()
+
+	client, found := p.impersonatingClients[identity]
+	if !found {
+		client, err = kubernetes.NewImpersonatingClient(p.RESTConfig, impersonationConfig)
+		if err != nil {
+			return result, err
+		}
+		p.impersonatingClients[identity] = client
+	}
+
+	return ruleClient{client: client, identity: identity}, nil
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"reflect"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+// ClusterProcessors keeps one processor per cluster declared in the config.
+// Processors are reused between loops while the definition of their cluster, or the client settings, do not change
+type ClusterProcessors struct {
+	processors map[string]*Processor
+}
+
+func NewClusterProcessors() *ClusterProcessors {
+	return &ClusterProcessors{
+		processors: make(map[string]*Processor),
+	}
+}
+
+// GetClusters return the clusters declared in the config.
+// When there is none, the cluster where Hitman runs is the only one
+func GetClusters(config *v1alpha1.ConfigT) []v1alpha1.ClusterT {
+	if len(config.Spec.Clusters) == 0 {
+		return []v1alpha1.ClusterT{{Name: v1alpha1.DefaultClusterName}}
+	}
+
+	return config.Spec.Clusters
+}
+
+// Get return the processors for the given clusters, in the same order.
+// Clusters whose processor can not be created are skipped, so the rest of them are still processed
+func (c *ClusterProcessors) Get(clusters []v1alpha1.ClusterT, clientSettings v1alpha1.KubernetesClientT) (processors []*Processor) {
+
+	currentProcessors := make(map[string]*Processor, len(clusters))
+
+	for _, cluster := range clusters {
+		processor, found := c.processors[cluster.Name]
+
+		if !found || !reflect.DeepEqual(processor.Cluster, cluster) || !reflect.DeepEqual(processor.ClientSettings, clientSettings) {
+			var err error
+			processor, err = NewProcessor(cluster, clientSettings)
+			if err != nil {
+				globals.ExecContext.Logger.Infow("error creating processor. Skipping cluster",
+					"cluster", cluster.Name, "error", err.Error())
+				continue
+			}
+		}
+
+		currentProcessors[cluster.Name] = processor
+		processors = append(processors, processor)
+	}
+
+	// Processors of removed clusters are forgotten
+	c.processors = currentProcessors
+
+	return processors
+}
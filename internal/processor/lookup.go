@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"fmt"
+	"strings"
+	gotemplate "text/template"
+
+	//
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	//
+	"go.uber.org/zap"
+
+	//
+	"hitman/internal/globals"
+	"hitman/internal/template"
+)
+
+// resetLookupCache forgets all the objects retrieved by 'lookup' template function.
+// It is called at the beginning of each synchronization loop, so templates see fresh cluster state on each loop
+// while the same object is requested only once per loop
+func (p *Processor) resetLookupCache() {
+	p.lookupCacheMutex.Lock()
+	defer p.lookupCacheMutex.Unlock()
+
+	p.lookupCache = make(map[string]map[string]interface{})
+}
+
+// getTemplateFunctions return the template functions that need the processor to work.
+// Given logger is used by 'logPrintf', so lines thrown from templates carry the context of the rule.
+// Given client is used to look up objects, so they are retrieved with the identity of the rule
+func (p *Processor) getTemplateFunctions(logger *zap.SugaredLogger, ruleClientObj ruleClient) gotemplate.FuncMap {
+	functions := gotemplate.FuncMap{
+		"lookup": func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+			return p.lookup(ruleClientObj, apiVersion, kind, namespace, name)
+		},
+		"ownerOf": func(object map[string]interface{}) (map[string]interface{}, error) {
+			return p.ownerOf(ruleClientObj, object)
+		},
+		"logPrintf": template.NewLogPrintf(logger),
+	}
+
+	// Time-related functions measure time from the instant of current loop, as '.now' does
+	for functionName, function := range template.GetClockFunctions(p.loopNow) {
+		functions[functionName] = function
+	}
+
+	return functions
+}
+
+// getResourceInterface return a client to perform requests against resources of the given apiVersion and kind.
+// When the resource is not namespaced, the namespace is ignored
+func (p *Processor) getResourceInterface(ruleClientObj ruleClient, apiVersion, kind, namespace string) (resourceInterface dynamic.ResourceInterface, err error) {
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+
+	mapping, err := p.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	// Discovery information is cached. Refresh it once in case the API was created later
+	if meta.IsNoMatchError(err) {
+		p.RESTMapper.Reset()
+		mapping, err = p.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+
+	if err != nil {
+		return resourceInterface, fmt.Errorf("error mapping kind '%s' in apiVersion '%s': %s", kind, apiVersion, err.Error())
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot || namespace == "" {
+		return ruleClientObj.client.Resource(mapping.Resource), nil
+	}
+
+	return ruleClientObj.client.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// lookup retrieves an object from the cluster following the same behavior as Helm:
+// an empty map is returned when the object does not exist, and a list is returned when the name is empty.
+// It only performs read-only requests, and results are cached during the synchronization loop for each identity
+// Ref: https://helm.sh/docs/chart_template_guide/functions_and_pipelines/#using-the-lookup-function
+//
+// This is designed to be called from a template.
+func (p *Processor) lookup(ruleClientObj ruleClient, apiVersion, kind, namespace, name string) (result map[string]interface{}, err error) {
+
+	// Identities can have different permissions, so they do not share cached objects
+	cacheKey := strings.Join([]string{ruleClientObj.identity, apiVersion, kind, namespace, name}, "/")
+
+	p.lookupCacheMutex.RLock()
+	cachedResult, found := p.lookupCache[cacheKey]
+	p.lookupCacheMutex.RUnlock()
+
+	if found {
+		return cachedResult, nil
+	}
+
+	resourceInterface, err := p.getResourceInterface(ruleClientObj, apiVersion, kind, namespace)
+	if err != nil {
+		return map[string]interface{}{}, err
+	}
+
+	var object interface {
+		UnstructuredContent() map[string]interface{}
+	}
+
+	if name == "" {
+		object, err = resourceInterface.List(globals.ExecContext.Context, v1.ListOptions{})
+	} else {
+		object, err = resourceInterface.Get(globals.ExecContext.Context, name, v1.GetOptions{})
+	}
+
+	switch {
+	case errors.IsNotFound(err):
+		result = map[string]interface{}{}
+	case err != nil:
+		return map[string]interface{}{}, fmt.Errorf("error looking up '%s': %s", cacheKey, err.Error())
+	default:
+		result = object.UnstructuredContent()
+	}
+
+	p.lookupCacheMutex.Lock()
+	p.lookupCache[cacheKey] = result
+	p.lookupCacheMutex.Unlock()
+
+	return result, nil
+}
+
+// ownerOf retrieves the owner flagged as controller of an object from the cluster.
+// When the object has no controller, an empty map is returned. Other owners are never followed,
+// as they do not manage the object (e.g. a ConfigMap owned by several Secrets)
+//
+// This is designed to be called from a template.
+func (p *Processor) ownerOf(ruleClientObj ruleClient, object map[string]interface{}) (result map[string]interface{}, err error) {
+	objectWrapper := unstructured.Unstructured{Object: object}
+
+	controllerReference := v1.GetControllerOfNoCopy(&objectWrapper)
+	if controllerReference == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	return p.lookup(ruleClientObj, controllerReference.APIVersion, controllerReference.Kind, objectWrapper.GetNamespace(), controllerReference.Name)
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"testing"
+
+	//
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOwnerOf(t *testing.T) {
+	controller := true
+
+	replicaSet := v1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "replicaset", Controller: &controller}
+	configMap := v1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "configmap"}
+
+	tests := []struct {
+		name            string
+		ownerReferences []v1.OwnerReference
+		wantOwner       string
+	}{
+		{"without owners", nil, ""},
+		{"controller", []v1.OwnerReference{replicaSet}, "replicaset"},
+		{"controller is not the first owner", []v1.OwnerReference{configMap, replicaSet}, "replicaset"},
+		{"owners without controller are not followed", []v1.OwnerReference{configMap}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &Processor{}
+			p.resetLookupCache()
+
+			// Owners are served from the cache, so the cluster is never reached
+			for _, ownerReference := range []v1.OwnerReference{replicaSet, configMap} {
+				owner := unstructured.Unstructured{Object: map[string]interface{}{}}
+				owner.SetName(ownerReference.Name)
+				p.lookupCache["/"+ownerReference.APIVersion+"/"+ownerReference.Kind+"/default/"+ownerReference.Name] = owner.Object
+			}
+
+			object := unstructured.Unstructured{Object: map[string]interface{}{}}
+			object.SetNamespace("default")
+			object.SetOwnerReferences(test.ownerReferences)
+
+			owner, err := p.ownerOf(ruleClient{}, object.Object)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if ownerName := (&unstructured.Unstructured{Object: owner}).GetName(); ownerName != test.wantOwner {
+				t.Fatalf("got owner '%s', want '%s'", ownerName, test.wantOwner)
+			}
+		})
+	}
+}
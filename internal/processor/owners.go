@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"fmt"
+
+	//
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+// isOwnersResolutionEnabled return true when the ownerReferences chain is needed for the given resource
+func isOwnersResolutionEnabled(ownersConfig v1alpha1.OwnersT) bool {
+	return ownersConfig.Resolve ||
+		ownersConfig.ActOn == v1alpha1.OwnersActOnTopController ||
+		len(ownersConfig.HealthyConditions) > 0
+}
+
+// resolveOwners walks the ownerReferences chain of an object following the owners flagged as controller.
+// Returned list is ordered from the direct owner to the top-level controller.
+// The walk stops when an object has no controller, or when its controller no longer exists in the cluster
+func (p *Processor) resolveOwners(ruleClientObj ruleClient, object unstructured.Unstructured, maxDepth int) (owners []unstructured.Unstructured, err error) {
+
+	if maxDepth <= 0 {
+		maxDepth = v1alpha1.DefaultOwnersMaxDepth
+	}
+
+	currentObject := object
+	for depth := 0; depth < maxDepth; depth++ {
+
+		owner, err := p.ownerOf(ruleClientObj, currentObject.Object)
+		if err != nil {
+			return owners, fmt.Errorf("error resolving owner of '%s/%s': %s",
+				currentObject.GetKind(), currentObject.GetName(), err.Error())
+		}
+
+		if len(owner) == 0 {
+			break
+		}
+
+		currentObject = unstructured.Unstructured{Object: owner}
+		owners = append(owners, currentObject)
+	}
+
+	return owners, nil
+}
+
+// getOwnersTemplateData converts a list of owners into a type that can be injected into templates
+func getOwnersTemplateData(owners []unstructured.Unstructured) []map[string]interface{} {
+	injectedOwnerList := []map[string]interface{}{}
+	for _, owner := range owners {
+		injectedOwnerList = append(injectedOwnerList, owner.Object)
+	}
+
+	return injectedOwnerList
+}
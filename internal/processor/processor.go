@@ -8,73 +8,249 @@ import (
 	"reflect"
 	"regexp"
 	"slices"
+	"sync"
 	"time"
 
 	//
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+
+	//
+	"go.uber.org/zap"
 
 	//
 	"hitman/api/v1alpha1"
+	"hitman/internal/audit"
+	"hitman/internal/backup"
 	"hitman/internal/globals"
 	"hitman/internal/kubernetes"
+	"hitman/internal/metrics"
+	"hitman/internal/rules"
 	"hitman/internal/template"
 )
 
+const (
+	// Actions and outcomes attached to log lines as structured fields
+	actionList     = "list"
+	actionPreStep  = "prestep"
+	actionEvaluate = "evaluate"
+	actionBackup   = "backup"
+	actionCondemn  = "condemn"
+	actionDelete   = "delete"
+
+	outcomeSuccess    = "success"
+	outcomeError      = "error"
+	outcomeSkipped    = "skipped"
+	outcomeNotMatched = "not-matched"
+	outcomeDryRun     = "dry-run"
+	outcomeCondemned  = "condemned"
+
+	// outcomeWouldCondemn is returned for objects that would have been condemned in dry-run mode.
+	// It is logged and audited as a dry-run outcome of the condemn action
+	outcomeWouldCondemn = "would-condemn"
+)
+
 type Processor struct {
-	Client *dynamic.DynamicClient
+	// Cluster where the rules are processed, and the settings used to connect to it
+	Cluster        v1alpha1.ClusterT
+	ClientSettings v1alpha1.KubernetesClientT
+
+	Client     *dynamic.DynamicClient
+	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
+
+	// Config used to create the clients of the cluster
+	RESTConfig *rest.Config
+
+	// Objects retrieved by 'lookup' template function during current loop
+	lookupCache      map[string]map[string]interface{}
+	lookupCacheMutex sync.RWMutex
+
+	// Objects killed during current loop
+	killedObjects map[types.UID]struct{}
+
+	// Condemned top-level controllers reached by the dependents of the rule being processed.
+	// Those with no dependent meeting the conditions are pardoned once all of them are processed
+	unmatchedControllers map[types.UID]unstructured.Unstructured
+	matchedControllers   map[types.UID]struct{}
+
+	// Instant every object of current loop is judged against by templates
+	loopNow time.Time
+
+	// Results of the last run of each rule, indexed by the name of the rule.
+	// Rules not processed in the cluster of this processor have no results
+	LastRunStatuses map[string]*v1alpha1.RuleStatusT
+
+	// Clients impersonating other identities, indexed by the impersonated identity
+	impersonatingClients      map[string]*dynamic.DynamicClient
+	impersonatingClientsMutex sync.Mutex
 }
 
-func NewProcessor() (processor *Processor, err error) {
+func NewProcessor(cluster v1alpha1.ClusterT, clientSettings v1alpha1.KubernetesClientT) (processor *Processor, err error) {
 
-	client, err := kubernetes.NewClient()
+	restConfig, err := kubernetes.GetClusterConfig(cluster, clientSettings)
+	if err != nil {
+		return processor, err
+	}
+
+	client, err := kubernetes.NewClient(restConfig)
+	if err != nil {
+		return processor, err
+	}
+
+	restMapper, err := kubernetes.NewRESTMapper(restConfig)
 	if err != nil {
 		return processor, err
 	}
 
 	return &Processor{
-		Client: client,
+		Cluster:        cluster,
+		ClientSettings: clientSettings,
+		Client:         client,
+		RESTMapper:     restMapper,
+		RESTConfig:     restConfig,
+		lookupCache:    make(map[string]map[string]interface{}),
+		killedObjects:  make(map[types.UID]struct{}),
+
+		impersonatingClients: make(map[string]*dynamic.DynamicClient),
 	}, err
 }
 
-// TODO
-func (p *Processor) SyncResources() (err error) {
-	for configResourceIndex, configResource := range globals.ExecContext.Config.Spec.Resources {
+// recordRuleError stores an error into the status of a rule
+func recordRuleError(ruleStatus *v1alpha1.RuleStatusT, message string) {
+	ruleStatus.Errors++
+	ruleStatus.LastError = message
+}
+
+// SyncResources processes the rules of a config snapshot that are due at this moment.
+// Rules not included in dueResourceIndexes keep their last results
+func (p *Processor) SyncResources(config *v1alpha1.ConfigT, dueResourceIndexes map[int]bool) (err error) {
+
+	// All the objects reviewed in the same loop are judged against the same instant
+	loopNow := template.Now()
+	p.loopNow = loopNow
+
+	// Objects looked up from templates, and objects already killed, are only remembered during current loop
+	p.resetLookupCache()
+	p.killedObjects = make(map[types.UID]struct{})
+
+	// Results of each rule are kept to be reported later. Those of rules removed from the config are forgotten
+	configRuleNames := map[string]bool{}
+	for configResourceIndex, configResource := range config.Spec.Resources {
+		configRuleNames[rules.GetName(configResourceIndex, configResource)] = true
+	}
+
+	if p.LastRunStatuses == nil {
+		p.LastRunStatuses = map[string]*v1alpha1.RuleStatusT{}
+	}
+
+	for ruleName := range p.LastRunStatuses {
+		if !configRuleNames[ruleName] {
+			delete(p.LastRunStatuses, ruleName)
+		}
+	}
+
+	// Results are exposed as metrics once the loop is done
+	defer p.recordMetrics(config, loopNow, dueResourceIndexes)
+
+	processedRules := 0
+	for configResourceIndex, configResource := range config.Spec.Resources {
+
+		// Rules run on their own schedules. Disabled ones never run
+		if !dueResourceIndexes[configResourceIndex] || !rules.IsEnabled(configResource) {
+			continue
+		}
+
+		// Rules can be scoped to some clusters
+		if len(configResource.Clusters) > 0 && !slices.Contains(configResource.Clusters, p.Cluster.Name) {
+			continue
+		}
 
 		// You may wonder why this is in the upper section of the loop...
 		// Fast solution, less canonical. Lets your tomorrow-me worry about that
-		if configResourceIndex != 0 {
-			time.Sleep(globals.ExecContext.Config.Spec.Synchronization.CarriedProcessingDelay)
+		if processedRules != 0 {
+			time.Sleep(config.Spec.Synchronization.CarriedProcessingDelay)
+		}
+		processedRules++
+
+		ruleName := rules.GetName(configResourceIndex, configResource)
+
+		ruleStatus := &v1alpha1.RuleStatusT{}
+		p.LastRunStatuses[ruleName] = ruleStatus
+		ruleStatus.Name = ruleName
+		ruleStatus.Cluster = p.Cluster.Name
+		ruleStatus.LastRun = loopNow.Format(time.RFC3339)
+		ruleStatus.DryRun = rules.IsDryRun(configResource)
+
+		// Get the resources of the target type
+		gvr := schema.GroupVersionResource{
+			Group:    configResource.Target.Group,
+			Version:  configResource.Target.Version,
+			Resource: configResource.Target.Resource,
+		}
+
+		// Every log line related to this rule carries its context
+		ruleLogger := globals.ExecContext.Logger.With(
+			"cluster", p.Cluster.Name,
+			"rule", ruleName,
+			"gvr", gvr.String(),
+			"dryRun", ruleStatus.DryRun,
+		)
+		if len(configResource.Labels) > 0 {
+			ruleLogger = ruleLogger.With("ruleLabels", configResource.Labels)
 		}
 
 		// Matching a name is required
 		if reflect.ValueOf(configResource.Target.Name).IsZero() {
-			globals.ExecContext.Logger.Infof("target name or namespace selector is missing for group '%s' version '%s' resource '%s'. Skipping",
-				configResource.Target.Group, configResource.Target.Version, configResource.Target.Resource)
+			ruleLogger.Infow("target name or namespace selector is missing. Skipping",
+				"outcome", outcomeSkipped)
+			recordRuleError(ruleStatus, "target name or namespace selector is missing")
 			continue
 		}
 
 		// Matching a name is required
 		if configResource.Target.Name.MatchExact != "" && configResource.Target.Name.MatchRegex != "" {
-			globals.ExecContext.Logger.Infof("target name can only have one selector: matchExact or matchRegex. Skipping")
+			ruleLogger.Infow("target name can only have one selector: matchExact or matchRegex. Skipping",
+				"outcome", outcomeSkipped)
+			recordRuleError(ruleStatus, "target name can only have one selector: matchExact or matchRegex")
 			continue
 		}
 
 		if configResource.Target.Namespace.MatchExact != "" && configResource.Target.Namespace.MatchRegex != "" {
-			globals.ExecContext.Logger.Infof("targets namespace can only have one selector: matchExact or matchRegex. Skipping")
+			ruleLogger.Infow("targets namespace can only have one selector: matchExact or matchRegex. Skipping",
+				"outcome", outcomeSkipped)
+			recordRuleError(ruleStatus, "targets namespace can only have one selector: matchExact or matchRegex")
 			continue
 		}
 
-		// Get the resources of the target type
-		gvr := schema.GroupVersionResource{
-			Group:    configResource.Target.Group,
-			Version:  configResource.Target.Version,
-			Resource: configResource.Target.Resource,
+		if configResource.Owners.ActOn != "" &&
+			configResource.Owners.ActOn != v1alpha1.OwnersActOnObject &&
+			configResource.Owners.ActOn != v1alpha1.OwnersActOnTopController {
+			message := fmt.Sprintf("owners can only act on '%s' or '%s'",
+				v1alpha1.OwnersActOnObject, v1alpha1.OwnersActOnTopController)
+			ruleLogger.Infow(message+". Skipping", "outcome", outcomeSkipped)
+			recordRuleError(ruleStatus, message)
+			continue
 		}
 
-		resourceRaw := p.Client.Resource(gvr)
+		// Rules are processed with the identity they declare, so its RBAC is enforced
+		ruleClientObj, err := p.getRuleClient(configResource)
+		if err != nil {
+			ruleLogger.Infow("error creating Kubernetes client for the rule",
+				"outcome", outcomeError, "error", err.Error())
+			recordRuleError(ruleStatus, fmt.Sprintf("error creating Kubernetes client for the rule: %s", err.Error()))
+			continue
+		}
+
+		if ruleClientObj.identity != "" {
+			ruleLogger = ruleLogger.With("identity", ruleClientObj.identity)
+		}
+
+		resourceRaw := ruleClientObj.client.Resource(gvr)
 
 		//
 		resourceList, err := resourceRaw.List(globals.ExecContext.Context, v1.ListOptions{})
@@ -84,21 +260,29 @@ func (p *Processor) SyncResources() (err error) {
 		}
 
 		if err != nil {
-			globals.ExecContext.Logger.Infof("error listing resources of type '%s' in namespace '%s': %s",
-				gvr.String(), configResource.Target.Namespace, err)
+			ruleLogger.Infow("error listing resources",
+				"namespace", configResource.Target.Namespace.MatchExact,
+				"action", actionList, "outcome", outcomeError, "error", err.Error())
+			recordRuleError(ruleStatus, fmt.Sprintf("error listing resources: %s", err.Error()))
 			continue
 		}
 
 		//
 		compiledRegex, err := regexp.Compile(configResource.Target.Name.MatchRegex)
 		if err != nil {
-			globals.ExecContext.Logger.Infof("error compiling regular expression '%s' for resource name: %s", configResource.Target.Name.MatchRegex, err)
+			ruleLogger.Infow("error compiling regular expression for resource name",
+				"regex", configResource.Target.Name.MatchRegex,
+				"outcome", outcomeError, "error", err.Error())
+			recordRuleError(ruleStatus, fmt.Sprintf("error compiling regular expression for resource name: %s", err.Error()))
 			continue
 		}
 
 		compiledRegexNamespace, err := regexp.Compile(configResource.Target.Namespace.MatchRegex)
 		if err != nil {
-			globals.ExecContext.Logger.Infof("error compiling regular expression '%s' for resource namespace: %s", configResource.Target.Name.MatchRegex, err)
+			ruleLogger.Infow("error compiling regular expression for resource namespace",
+				"regex", configResource.Target.Namespace.MatchRegex,
+				"outcome", outcomeError, "error", err.Error())
+			recordRuleError(ruleStatus, fmt.Sprintf("error compiling regular expression for resource namespace: %s", err.Error()))
 			continue
 		}
 
@@ -127,44 +311,102 @@ func (p *Processor) SyncResources() (err error) {
 			filteredResourceList = append(filteredResourceList, rawResourceObject)
 		}
 
-		templateInjectedObject := &map[string]interface{}{} // TODO, review potential nil pointer dereference
+		templateInjectedObject := &map[string]interface{}{ // TODO, review potential nil pointer dereference
+			"now": loopNow,
+		}
 
 		// Perform global user-defined actions when 'preStep' is set in the config
 		// This is useful to group resources, pre-filter some of them, etc, before evaluating one by one
 		if configResource.PreStep != "" {
-			err = p.processPrestep(configResource.PreStep, templateInjectedObject, filteredResourceList)
+			err = p.processPrestep(ruleLogger, ruleClientObj, configResource.PreStep, templateInjectedObject, filteredResourceList)
 			if err != nil {
-				globals.ExecContext.Logger.Infof("error processing prestep: %s", err)
+				ruleLogger.Infow("error processing prestep",
+					"action", actionPreStep, "outcome", outcomeError, "error", err.Error())
+				recordRuleError(ruleStatus, err.Error())
 				continue
 			}
 		}
 
+		p.unmatchedControllers = make(map[types.UID]unstructured.Unstructured)
+		p.matchedControllers = make(map[types.UID]struct{})
+
 		// Perform the actions over the resources
 		for _, resource := range filteredResourceList {
 
+			objectLogger := ruleLogger.With(
+				"namespace", resource.GetNamespace(),
+				"name", resource.GetName(),
+			)
+
+			// Actions performed over this object are audited with the context of the rule
+			auditEvent := audit.EventT{
+				Cluster:    p.Cluster.Name,
+				Rule:       ruleName,
+				RuleLabels: configResource.Labels,
+				Identity:   ruleClientObj.identity,
+				GVR:        gvr.String(),
+				DryRun:     rules.IsDryRun(configResource),
+			}
+
 			// Process this object. Delete in case of success
-			objectDeleted, err := p.processObject(gvr, resource, templateInjectedObject, configResource.Conditions)
+			outcome, err := p.processObject(objectLogger, ruleClientObj, gvr, resource, templateInjectedObject, configResource, auditEvent)
 			if err != nil {
-				globals.ExecContext.Logger.Infof("error processing object: %s", err)
+				objectLogger.Infow("error processing object",
+					"action", actionEvaluate, "outcome", outcomeError, "error", err.Error())
+				recordRuleError(ruleStatus, err.Error())
 				continue
 			}
 
-			if !objectDeleted {
-				globals.ExecContext.Logger.Debugf("resource '%s' in namespace '%s' did NOT meet the conditions",
-					resource.GetName(), resource.GetNamespace())
+			if outcome == outcomeSuccess || outcome == outcomeDryRun || outcome == outcomeCondemned || outcome == outcomeWouldCondemn {
+				ruleStatus.Matched++
+			}
+
+			if outcome == outcomeCondemned {
+				ruleStatus.Condemned++
+			}
+
+			// Shadow rules report what they would have done, so they can be compared before arming them
+			if outcome == outcomeDryRun {
+				ruleStatus.WouldKill++
+			}
+
+			if outcome == outcomeWouldCondemn {
+				ruleStatus.WouldCondemn++
+			}
+
+			if outcome != outcomeSuccess {
 				continue
 			}
 
-			globals.ExecContext.Logger.Infof("resource '%s'/'%s' in namespace '%s' was deleted successfully", resource.GetKind(), resource.GetName(), resource.GetNamespace())
+			ruleStatus.Killed++
+			objectLogger.Infow("object was deleted successfully",
+				"kind", resource.GetKind(), "action", actionDelete, "outcome", outcomeSuccess)
 		}
+
+		p.pardonControllers(ruleLogger, ruleClientObj, configResource)
+
+		ruleLogger.Infow("rule processed",
+			"matched", ruleStatus.Matched, "killed", ruleStatus.Killed, "condemned", ruleStatus.Condemned,
+			"wouldKill", ruleStatus.WouldKill, "wouldCondemn", ruleStatus.WouldCondemn, "errors", ruleStatus.Errors)
 	}
 
 	return err
 }
 
+// recordMetrics exposes the results of the rules processed on last loop as metrics
+func (p *Processor) recordMetrics(config *v1alpha1.ConfigT, loopNow time.Time, dueResourceIndexes map[int]bool) {
+	for configResourceIndex, configResource := range config.Spec.Resources {
+		ruleStatus, found := p.LastRunStatuses[rules.GetName(configResourceIndex, configResource)]
+		if !found || !dueResourceIndexes[configResourceIndex] {
+			continue
+		}
+		metrics.RecordRuleStatus(*ruleStatus, float64(loopNow.Unix()))
+	}
+}
+
 // processPrestep process a list with all the user-desired targets
 // It receive the .targets and is able to store variables inside .vars that are available into conditions' later evaluation
-func (p *Processor) processPrestep(userTemplate string, templateInjectedData *map[string]interface{}, targetList []unstructured.Unstructured) (err error) {
+func (p *Processor) processPrestep(logger *zap.SugaredLogger, ruleClientObj ruleClient, userTemplate string, templateInjectedData *map[string]interface{}, targetList []unstructured.Unstructured) (err error) {
 
 	// Convert injected data into allowed type
 	injectedTargetList := []map[string]interface{}{}
@@ -175,7 +417,7 @@ func (p *Processor) processPrestep(userTemplate string, templateInjectedData *ma
 	//
 	(*templateInjectedData)["targets"] = injectedTargetList
 
-	_, err = template.EvaluateTemplate(userTemplate, templateInjectedData)
+	_, err = template.EvaluateTemplate(userTemplate, templateInjectedData, p.getTemplateFunctions(logger, ruleClientObj))
 	if err != nil {
 		return fmt.Errorf("error evaluating prestep template: %s", err.Error())
 	}
@@ -185,55 +427,202 @@ func (p *Processor) processPrestep(userTemplate string, templateInjectedData *ma
 	return nil
 }
 
+// evaluateConditions evaluates a list of conditions against the injected data.
+// It returns true only when all of them are met, and the result of each one of them
+func (p *Processor) evaluateConditions(logger *zap.SugaredLogger, ruleClientObj ruleClient, conditionList []v1alpha1.ConditionT, templateInjectedData *map[string]interface{}) (result bool, conditionResults []audit.ConditionResultT, err error) {
+
+	var conditionFlags []bool
+
+	for _, condition := range conditionList {
+
+		parsedKey, err := template.EvaluateTemplate(condition.Key, templateInjectedData, p.getTemplateFunctions(logger, ruleClientObj))
+		if err != nil {
+			return false, conditionResults, fmt.Errorf("error evaluating condition template: %s", err)
+		}
+
+		conditionFlags = append(conditionFlags, parsedKey == condition.Value)
+		conditionResults = append(conditionResults, audit.ConditionResultT{
+			Key:    condition.Key,
+			Value:  condition.Value,
+			Result: parsedKey,
+			Met:    parsedKey == condition.Value,
+		})
+
+		logger.Debugw("condition evaluated",
+			"key", parsedKey, "value", condition.Value, "equals", parsedKey == condition.Value)
+	}
+
+	return !slices.Contains(conditionFlags, false), conditionResults, nil
+}
+
 // processObject process an object coming from arguments.
-// It computes templating, evaluates conditions and decides whether to delete it or not.
-func (p *Processor) processObject(gvr schema.GroupVersionResource, object unstructured.Unstructured, templateInjectedData *map[string]interface{}, conditionList []v1alpha1.ConditionT) (result bool, err error) {
+// It computes templating, evaluates conditions and decides whether to delete it or not, returning the outcome.
+// When configured, the owners of the object are resolved and the top-level controller is deleted instead.
+// Actions performed over the object are audited using the given event as base
+func (p *Processor) processObject(logger *zap.SugaredLogger, ruleClientObj ruleClient, gvr schema.GroupVersionResource, object unstructured.Unstructured, templateInjectedData *map[string]interface{}, configResource v1alpha1.ResourceT, auditEvent audit.EventT) (outcome string, err error) {
 
-	globals.ExecContext.Logger.Debugf("processing object: group: '%s', version: '%s', resource: '%s', name: '%s', namespace: '%s'",
-		gvr.Group, gvr.Version, gvr.Resource, object.GetName(), object.GetNamespace())
+	logger.Debugw("processing object", "action", actionEvaluate)
 
 	// Create the object that will be injected on templating system
 	(*templateInjectedData)["object"] = object.Object
 
-	// Evaluate the conditions for targeted object
-	var conditionFlags []bool
+	// Resolve the owners chain only when needed, as it requires extra requests to Kubernetes
+	var owners []unstructured.Unstructured
 
-	for _, condition := range conditionList {
+	delete(*templateInjectedData, "owners")
+	delete(*templateInjectedData, "owner")
 
-		parsedKey, err := template.EvaluateTemplate(condition.Key, templateInjectedData)
+	if isOwnersResolutionEnabled(configResource.Owners) {
+		owners, err = p.resolveOwners(ruleClientObj, object, configResource.Owners.MaxDepth)
 		if err != nil {
-			return false, fmt.Errorf("error evaluating condition template: %s", err)
+			return outcomeError, err
 		}
 
-		conditionFlags = append(conditionFlags, parsedKey == condition.Value)
+		(*templateInjectedData)["owners"] = getOwnersTemplateData(owners)
+		if len(owners) > 0 {
+			(*templateInjectedData)["owner"] = owners[0].Object
+		}
+	}
 
-		globals.ExecContext.Logger.Debugf("condition: key: '%s', value: '%s', equals: '%t'",
-			parsedKey, condition.Value, parsedKey == condition.Value)
+	// Owner is still healthy. Skip
+	if len(configResource.Owners.HealthyConditions) > 0 && len(owners) > 0 {
+		ownerHealthy, _, err := p.evaluateConditions(logger, ruleClientObj, configResource.Owners.HealthyConditions, templateInjectedData)
+		if err != nil {
+			return outcomeError, fmt.Errorf("error evaluating owner healthy conditions: %s", err)
+		}
+
+		if ownerHealthy {
+			logger.Debugw("owner is healthy. Skipping",
+				"owner", owners[0].GetKind()+"/"+owners[0].GetName(),
+				"action", actionEvaluate, "outcome", outcomeSkipped)
+			p.pardonObject(logger, ruleClientObj, gvr, object, owners, configResource)
+			return outcomeSkipped, nil
+		}
 	}
 
-	// Conditions not met. Skip
-	if slices.Contains(conditionFlags, false) {
-		return false, nil
+	// Evaluate the conditions for targeted object
+	conditionsMet, conditionResults, err := p.evaluateConditions(logger, ruleClientObj, configResource.Conditions, templateInjectedData)
+	if err != nil {
+		return outcomeError, err
 	}
 
-	if globals.ExecContext.DryRun {
-		globals.ExecContext.Logger.Infof("dry-run enabled. Skipping deletion of object: '%s'/'%s'/'%s'",
-			object.GetNamespace(), object.GetKind(), object.GetName())
-		return false, nil
+	// Conditions not met. Skip
+	if !conditionsMet {
+		logger.Debugw("object did NOT meet the conditions",
+			"action", actionEvaluate, "outcome", outcomeNotMatched)
+		p.pardonObject(logger, ruleClientObj, gvr, object, owners, configResource)
+		return outcomeNotMatched, nil
 	}
 
 	// Define a grace period (in seconds) for the pod deletion
 	// Ref: https://github.com/kubernetes/apimachinery/blob/master/pkg/apis/meta/v1/types.go#L507
 	gracePeriodSeconds := int64(0) // 0 for immediate deletion
 
+	deleteOptions := v1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriodSeconds,
+	}
+
+	// Select the object to delete: the targeted one, or its top-level controller
+	killedObject := object
+
+	// Every action performed from now on is recorded
+	auditEvent.Conditions = conditionResults
+	defer func() {
+		recordEvent(auditEvent, killedObject, outcome, err)
+	}()
+
+	killedResource := ruleClientObj.client.Resource(gvr).Namespace(object.GetNamespace())
+
+	if configResource.Owners.ActOn == v1alpha1.OwnersActOnTopController && len(owners) > 0 {
+		killedObject = owners[len(owners)-1]
+		p.matchedControllers[killedObject.GetUID()] = struct{}{}
+
+		killedResource, err = p.getResourceInterface(ruleClientObj, killedObject.GetAPIVersion(), killedObject.GetKind(), killedObject.GetNamespace())
+		if err != nil {
+			return outcomeError, err
+		}
+
+		// Dependents of the controller are deleted too, so they are not left orphan
+		propagationPolicy := v1.DeletePropagationBackground
+		deleteOptions.PropagationPolicy = &propagationPolicy
+
+		logger = logger.With("controller", killedObject.GetKind()+"/"+killedObject.GetName())
+		logger.Infow("object is owned by a top-level controller. Acting on it", "action", actionDelete)
+	}
+
+	// Several objects can share the same controller. Kill it only once per loop
+	if _, alreadyKilled := p.killedObjects[killedObject.GetUID()]; alreadyKilled {
+		logger.Debugw("object was already killed in this loop. Skipping",
+			"action", actionDelete, "outcome", outcomeSkipped)
+		return outcomeSkipped, nil
+	}
+
+	// Owners can prevent their objects from being killed
+	if isVetoed(object, killedObject) {
+		logger.Infow("object was vetoed by its owners. Skipping",
+			"annotation", v1alpha1.VetoAnnotation, "action", actionDelete, "outcome", outcomeSkipped)
+		return outcomeSkipped, nil
+	}
+
+	// High-risk rules condemn objects first. They are only killed when they still match after the grace period
+	if isQuarantineEnabled(configResource.Quarantine) {
+		// Objects with a malformed label are condemned again, so the label is rewritten once
+		condemnedAt, _ := getCondemnedAt(killedObject)
+
+		if condemnedAt.IsZero() {
+			if rules.IsDryRun(configResource) {
+				logger.Infow("dry-run enabled. Object would have been condemned",
+					"kind", killedObject.GetKind(), "action", actionCondemn, "outcome", outcomeDryRun)
+				p.killedObjects[killedObject.GetUID()] = struct{}{}
+				return outcomeWouldCondemn, nil
+			}
+
+			err = condemnObject(killedResource, killedObject, p.loopNow)
+			if err != nil {
+				return outcomeError, fmt.Errorf("error condemning object: %s", err)
+			}
+
+			logger.Infow("object was condemned. It will be killed when it still matches after the grace period",
+				"gracePeriod", configResource.Quarantine.GracePeriod, "action", actionCondemn, "outcome", outcomeCondemned)
+			return outcomeCondemned, nil
+		}
+
+		if p.loopNow.Sub(condemnedAt) < configResource.Quarantine.CarriedGracePeriod {
+			logger.Debugw("object is condemned but its grace period is not over. Skipping",
+				"condemnedAt", condemnedAt.Format(time.RFC3339), "action", actionCondemn, "outcome", outcomeSkipped)
+			return outcomeSkipped, nil
+		}
+	}
+
+	if rules.IsDryRun(configResource) {
+		logger.Infow("dry-run enabled. Object would have been killed",
+			"kind", killedObject.GetKind(), "action", actionDelete, "outcome", outcomeDryRun)
+
+		// Shared controllers are reported only once, as they would be killed only once
+		p.killedObjects[killedObject.GetUID()] = struct{}{}
+		return outcomeDryRun, nil
+	}
+
+	// Keep a copy of the object, so it can be restored later. What can not be backed up is never deleted
+	if configResource.Backup.Type != "" {
+		backupRecord := backup.NewRecord(auditEvent.Cluster, auditEvent.Rule, killedObject)
+
+		err = backup.Save(ruleClientObj.client, configResource.Backup, backupRecord)
+		if err != nil {
+			return outcomeError, fmt.Errorf("error backing up object. Skipping deletion: %s", err)
+		}
+
+		logger.Debugw("object was backed up",
+			"backup", configResource.Backup.Type, "action", actionBackup, "outcome", outcomeSuccess)
+	}
+
 	// Finally, delete the object
-	err = p.Client.Resource(gvr).Namespace(object.GetNamespace()).
-		Delete(globals.ExecContext.Context, object.GetName(), v1.DeleteOptions{
-			GracePeriodSeconds: &gracePeriodSeconds,
-		})
+	err = killedResource.Delete(globals.ExecContext.Context, killedObject.GetName(), deleteOptions)
 	if err != nil {
-		return false, fmt.Errorf("error deleting object: %s", err)
+		return outcomeError, fmt.Errorf("error deleting object: %s", err)
 	}
 
-	return true, nil
+	p.killedObjects[killedObject.GetUID()] = struct{}{}
+
+	return outcomeSuccess, nil
 }
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	//
+	"go.uber.org/zap"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+	"hitman/internal/rules"
+)
+
+// isQuarantineEnabled return true when objects must be condemned before being killed
+func isQuarantineEnabled(quarantineConfig v1alpha1.QuarantineT) bool {
+	return quarantineConfig.GracePeriod != ""
+}
+
+// isVetoed return true when the owners of any of the given objects vetoed their kill
+func isVetoed(objects ...unstructured.Unstructured) bool {
+	for _, object := range objects {
+		if _, vetoed := object.GetAnnotations()[v1alpha1.VetoAnnotation]; vetoed {
+			return true
+		}
+	}
+	return false
+}
+
+// getCondemnedAt return the time when an object was condemned, and whether it is condemned at all.
+// The time is zero when the label is malformed, so the object is condemned again and the grace period is respected
+func getCondemnedAt(object unstructured.Unstructured) (condemnedAt time.Time, condemned bool) {
+	condemnedAtLabel, found := object.GetLabels()[v1alpha1.CondemnedAtLabel]
+	if !found {
+		return condemnedAt, false
+	}
+
+	condemnedAtUnix, err := strconv.ParseInt(condemnedAtLabel, 10, 64)
+	if err != nil {
+		return condemnedAt, true
+	}
+
+	return time.Unix(condemnedAtUnix, 0), true
+}
+
+// patchCondemnedAtLabel sets the label of condemned objects. Nil value removes it
+func patchCondemnedAtLabel(resourceInterface dynamic.ResourceInterface, object unstructured.Unstructured, value interface{}) (err error) {
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				v1alpha1.CondemnedAtLabel: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = resourceInterface.Patch(globals.ExecContext.Context, object.GetName(), types.MergePatchType, patchBytes, v1.PatchOptions{})
+	return err
+}
+
+// condemnObject labels an object with the time it was condemned at.
+// Unix timestamps are used, as RFC3339 times are not valid label values
+func condemnObject(resourceInterface dynamic.ResourceInterface, object unstructured.Unstructured, now time.Time) error {
+	return patchCondemnedAtLabel(resourceInterface, object, fmt.Sprint(now.Unix()))
+}
+
+// pardonObject removes the condemnation of an object that no longer meets the conditions.
+// Top-level controllers are only remembered, as some of their other dependents may still meet them.
+// Objects without owners are their own kill target, so they are pardoned right away
+func (p *Processor) pardonObject(logger *zap.SugaredLogger, ruleClientObj ruleClient, gvr schema.GroupVersionResource, object unstructured.Unstructured, owners []unstructured.Unstructured, configResource v1alpha1.ResourceT) {
+
+	if !isQuarantineEnabled(configResource.Quarantine) || rules.IsDryRun(configResource) {
+		return
+	}
+
+	if configResource.Owners.ActOn == v1alpha1.OwnersActOnTopController && len(owners) > 0 {
+		controller := owners[len(owners)-1]
+		if _, condemned := getCondemnedAt(controller); condemned {
+			p.unmatchedControllers[controller.GetUID()] = controller
+		}
+		return
+	}
+
+	if _, condemned := getCondemnedAt(object); !condemned {
+		return
+	}
+
+	err := patchCondemnedAtLabel(ruleClientObj.client.Resource(gvr).Namespace(object.GetNamespace()), object, nil)
+	if err != nil {
+		logger.Infow("error pardoning condemned object", "action", actionCondemn, "outcome", outcomeError, "error", err.Error())
+		return
+	}
+
+	logger.Infow("object no longer meets the conditions. It was pardoned", "action", actionCondemn, "outcome", outcomeSuccess)
+}
+
+// pardonControllers removes the condemnation of the top-level controllers whose dependents
+// were processed by the rule, when none of them met the conditions
+func (p *Processor) pardonControllers(logger *zap.SugaredLogger, ruleClientObj ruleClient, configResource v1alpha1.ResourceT) {
+
+	for controllerUID, controller := range p.unmatchedControllers {
+		if _, matched := p.matchedControllers[controllerUID]; matched {
+			continue
+		}
+
+		controllerLogger := logger.With("namespace", controller.GetNamespace(),
+			"controller", controller.GetKind()+"/"+controller.GetName())
+
+		resourceInterface, err := p.getResourceInterface(ruleClientObj, controller.GetAPIVersion(), controller.GetKind(), controller.GetNamespace())
+		if err == nil {
+			err = patchCondemnedAtLabel(resourceInterface, controller, nil)
+		}
+
+		if err != nil {
+			controllerLogger.Infow("error pardoning condemned controller", "action", actionCondemn, "outcome", outcomeError, "error", err.Error())
+			continue
+		}
+
+		controllerLogger.Infow("no dependent of the controller meets the conditions. It was pardoned", "action", actionCondemn, "outcome", outcomeSuccess)
+	}
+}
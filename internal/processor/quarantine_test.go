@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package processor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	//
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+// patchRecorderT is a fake Kubernetes API that records the patches it receives
+type patchRecorderT struct {
+	mutex   sync.Mutex
+	patches map[string]string
+}
+
+// newTestClient return a client talking to a fake Kubernetes API, and the recorder of the patches it receives
+func newTestClient(t *testing.T) (*dynamic.DynamicClient, *patchRecorderT) {
+	t.Helper()
+
+	recorder := &patchRecorderT{patches: map[string]string{}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		if request.Method == http.MethodPatch {
+			recorder.mutex.Lock()
+			recorder.patches[request.URL.Path] = string(body)
+			recorder.mutex.Unlock()
+		}
+
+		response.Header().Set("Content-Type", "application/json")
+		_, _ = response.Write([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"patched"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("error creating client: %s", err)
+	}
+
+	return client, recorder
+}
+
+// newCondemnedObject return an object condemned by a rule with quarantine
+func newCondemnedObject(apiVersion, kind, name string, uid types.UID) unstructured.Unstructured {
+	object := unstructured.Unstructured{}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace("default")
+	object.SetName(name)
+	object.SetUID(uid)
+	object.SetLabels(map[string]string{v1alpha1.CondemnedAtLabel: "1767225600"})
+
+	return object
+}
+
+func TestPardonObject(t *testing.T) {
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	pod := newCondemnedObject("v1", "Pod", "pod", "pod-uid")
+	controller := newCondemnedObject("apps/v1", "Deployment", "controller", "controller-uid")
+
+	tests := []struct {
+		name             string
+		actOn            string
+		owners           []unstructured.Unstructured
+		wantPatchedPaths []string
+		wantRemembered   []types.UID
+	}{
+		{
+			name:             "object",
+			actOn:            v1alpha1.OwnersActOnObject,
+			wantPatchedPaths: []string{"/api/v1/namespaces/default/pods/pod"},
+		},
+		{
+			name:           "top controller is remembered until every dependent is processed",
+			actOn:          v1alpha1.OwnersActOnTopController,
+			owners:         []unstructured.Unstructured{controller},
+			wantRemembered: []types.UID{"controller-uid"},
+		},
+		{
+			name:             "object without owners acting on top controller",
+			actOn:            v1alpha1.OwnersActOnTopController,
+			wantPatchedPaths: []string{"/api/v1/namespaces/default/pods/pod"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, recorder := newTestClient(t)
+
+			p := &Processor{unmatchedControllers: map[types.UID]unstructured.Unstructured{}}
+			configResource := v1alpha1.ResourceT{
+				Quarantine: v1alpha1.QuarantineT{GracePeriod: "1h"},
+				Owners:     v1alpha1.OwnersT{ActOn: test.actOn},
+			}
+
+			p.pardonObject(zap.NewNop().Sugar(), ruleClient{client: client}, podGVR, pod, test.owners, configResource)
+
+			if len(recorder.patches) != len(test.wantPatchedPaths) {
+				t.Fatalf("got patches %v, want them on %v", recorder.patches, test.wantPatchedPaths)
+			}
+
+			for _, path := range test.wantPatchedPaths {
+				if recorder.patches[path] != `{"metadata":{"labels":{"hitman.io/condemned-at":null}}}` {
+					t.Fatalf("got patch '%s' on %s, want the condemnation removed", recorder.patches[path], path)
+				}
+			}
+
+			if len(p.unmatchedControllers) != len(test.wantRemembered) {
+				t.Fatalf("got %d remembered controllers, want %v", len(p.unmatchedControllers), test.wantRemembered)
+			}
+
+			for _, uid := range test.wantRemembered {
+				if _, found := p.unmatchedControllers[uid]; !found {
+					t.Fatalf("controller %s was not remembered", uid)
+				}
+			}
+		})
+	}
+}
+
+func TestGetCondemnedAt(t *testing.T) {
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		wantAt        time.Time
+		wantCondemned bool
+	}{
+		{"not condemned", nil, time.Time{}, false},
+		{"condemned", map[string]string{v1alpha1.CondemnedAtLabel: "1767225600"}, time.Unix(1767225600, 0), true},
+		{"malformed label is condemned again", map[string]string{v1alpha1.CondemnedAtLabel: "yesterday"}, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := unstructured.Unstructured{Object: map[string]interface{}{}}
+			object.SetLabels(test.labels)
+
+			condemnedAt, condemned := getCondemnedAt(object)
+			if !condemnedAt.Equal(test.wantAt) || condemned != test.wantCondemned {
+				t.Fatalf("got %s and %t, want %s and %t", condemnedAt, condemned, test.wantAt, test.wantCondemned)
+			}
+		})
+	}
+}
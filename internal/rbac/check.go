@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"fmt"
+
+	//
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	//
+	"hitman/internal/globals"
+	"hitman/internal/kubernetes"
+)
+
+// GetMissingPermissions asks a cluster whether Hitman's own identity is allowed to perform
+// the given permissions, and return the ones that are denied
+// Ref: https://kubernetes.io/docs/reference/access-authn-authz/authorization/#checking-api-access
+func GetMissingPermissions(config *rest.Config, permissions []PermissionT) (missingPermissions []PermissionT, err error) {
+
+	client, err := kubernetes.NewClientset(config)
+	if err != nil {
+		return missingPermissions, err
+	}
+
+	reviewedPermissions := map[PermissionT]struct{}{}
+
+	for _, permission := range permissions {
+
+		// Same permission can be needed by several rules. Ask only once
+		if _, reviewed := reviewedPermissions[permission]; reviewed {
+			continue
+		}
+		reviewedPermissions[permission] = struct{}{}
+
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: permission.Namespace,
+					Verb:      permission.Verb,
+					Group:     permission.Group,
+					Resource:  permission.Resource,
+					Name:      permission.ResourceName,
+				},
+			},
+		}
+
+		review, err = client.AuthorizationV1().SelfSubjectAccessReviews().
+			Create(globals.ExecContext.Context, review, v1.CreateOptions{})
+		if err != nil {
+			return missingPermissions, fmt.Errorf("error reviewing access for verb '%s' on '%s': %s",
+				permission.Verb, permission.Resource, err.Error())
+		}
+
+		if !review.Status.Allowed {
+			missingPermissions = append(missingPermissions, permission)
+		}
+	}
+
+	return missingPermissions, nil
+}
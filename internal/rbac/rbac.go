@@ -0,0 +1,293 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	//
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/kubernetes"
+	"hitman/internal/rules"
+	"hitman/internal/template"
+)
+
+const (
+	VerbGet         = "get"
+	VerbList        = "list"
+	VerbWatch       = "watch"
+	VerbPatch       = "patch"
+	VerbCreate      = "create"
+	VerbDelete      = "delete"
+	VerbImpersonate = "impersonate"
+)
+
+// PermissionT defines a single permission needed by Hitman to process the config
+type PermissionT struct {
+	Group        string
+	Resource     string
+	ResourceName string
+
+	// Namespace is empty for permissions needed cluster-wide
+	Namespace string
+	Verb      string
+}
+
+// GetPermissions return the permissions needed by Hitman to process the resources of a config.
+// When a cluster name is given, only the resources processed in that cluster are taken into account.
+// Some permissions can not be known without evaluating the config against a cluster (e.g. owners' kinds).
+// Those cases are returned as warnings
+func GetPermissions(config *v1alpha1.ConfigT, clusterName string) (permissions []PermissionT, warnings []string) {
+
+	for resourceIndex, resource := range config.Spec.Resources {
+
+		if !rules.IsEnabled(resource) {
+			continue
+		}
+
+		if clusterName != "" && len(resource.Clusters) > 0 && !slices.Contains(resource.Clusters, clusterName) {
+			continue
+		}
+
+		ruleName := rules.GetName(resourceIndex, resource)
+
+		// Impersonated identities are authorized on their own. Hitman only needs to impersonate them
+		if rules.IsImpersonating(resource) {
+			impersonationConfig, err := rules.GetImpersonationConfig(resource.Impersonate)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", ruleName, err.Error()))
+				continue
+			}
+
+			permissions = append(permissions, getImpersonationPermissions(impersonationConfig)...)
+			warnings = append(warnings, fmt.Sprintf("%s: processed impersonating another identity. "+
+				"Permissions over targets must be granted to that identity", ruleName))
+			continue
+		}
+
+		// Targets are listed in one namespace or cluster-wide depending on the namespace selector
+		targetPermission := PermissionT{
+			Group:     resource.Target.Group,
+			Resource:  resource.Target.Resource,
+			Namespace: resource.Target.Namespace.MatchExact,
+		}
+
+		targetVerbs := []string{VerbList, VerbDelete}
+
+		// Condemned objects are labelled
+		if resource.Quarantine.GracePeriod != "" {
+			targetVerbs = append(targetVerbs, VerbPatch)
+		}
+
+		for _, verb := range targetVerbs {
+			targetPermission.Verb = verb
+			permissions = append(permissions, targetPermission)
+		}
+
+		// Backups can be stored in the cluster
+		switch resource.Backup.Type {
+		case v1alpha1.BackupTypeConfigMap:
+			permissions = append(permissions, PermissionT{Resource: "configmaps", Namespace: resource.Backup.Namespace, Verb: VerbCreate})
+		case v1alpha1.BackupTypeSecret:
+			permissions = append(permissions, PermissionT{Resource: "secrets", Namespace: resource.Backup.Namespace, Verb: VerbCreate})
+		}
+
+		// Looked up objects are only known when apiVersion and kind are literal strings
+		templates := []string{resource.PreStep}
+		for _, condition := range slices.Concat(resource.Conditions, resource.Owners.HealthyConditions) {
+			templates = append(templates, condition.Key)
+		}
+
+		for _, templateString := range templates {
+			lookupPermissions, lookupWarnings := getLookupPermissions(templateString)
+			permissions = append(permissions, lookupPermissions...)
+
+			for _, lookupWarning := range lookupWarnings {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", ruleName, lookupWarning))
+			}
+		}
+
+		if resource.Owners.Resolve || resource.Owners.ActOn == v1alpha1.OwnersActOnTopController ||
+			len(resource.Owners.HealthyConditions) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: owners are resolved. "+
+				"Permissions to 'get' (and 'delete' when acting on top controller) their kinds must be added by hand", ruleName))
+		}
+	}
+
+	// Snippets can be included from any rule, so their lookups are taken into account too
+	snippetNames := make([]string, 0, len(config.Spec.Templates))
+	for name := range config.Spec.Templates {
+		snippetNames = append(snippetNames, name)
+	}
+	sort.Strings(snippetNames)
+
+	for _, name := range snippetNames {
+		lookupPermissions, lookupWarnings := getLookupPermissions(config.Spec.Templates[name])
+		permissions = append(permissions, lookupPermissions...)
+
+		for _, lookupWarning := range lookupWarnings {
+			warnings = append(warnings, fmt.Sprintf("templates[%s]: %s", name, lookupWarning))
+		}
+	}
+
+	return permissions, warnings
+}
+
+// GetClustersPermissions return the permissions needed by Hitman to read the credentials of the clusters
+// declared in a config. They are needed in the cluster where Hitman runs
+func GetClustersPermissions(config *v1alpha1.ConfigT) (permissions []PermissionT) {
+	for _, cluster := range config.Spec.Clusters {
+		if cluster.SecretRef.Name == "" {
+			continue
+		}
+
+		permissions = append(permissions, PermissionT{
+			Resource: "secrets", ResourceName: cluster.SecretRef.Name,
+			Namespace: cluster.SecretRef.Namespace, Verb: VerbGet,
+		})
+	}
+
+	return permissions
+}
+
+// GetControllerPermissions return the permissions needed by Hitman to read its config from Kubernetes objects
+// and report their status. Rules of policies impersonate ServiceAccounts chosen by tenants in their namespaces,
+// which are unknown until policies are created, so any ServiceAccount can be impersonated
+func GetControllerPermissions() (permissions []PermissionT) {
+	permissions = append(permissions, PermissionT{Resource: "serviceaccounts", Verb: VerbImpersonate})
+
+	for _, resource := range []string{v1alpha1.ResourceHitman, v1alpha1.ResourceHitmanPolicy} {
+		for _, verb := range []string{VerbList, VerbWatch} {
+			permissions = append(permissions, PermissionT{Group: v1alpha1.Group, Resource: resource, Verb: verb})
+		}
+
+		permissions = append(permissions, PermissionT{Group: v1alpha1.Group, Resource: resource + "/status", Verb: VerbPatch})
+	}
+
+	return permissions
+}
+
+// getImpersonationPermissions return the permissions needed to impersonate an identity,
+// computed from the impersonation config actually sent to Kubernetes
+func getImpersonationPermissions(impersonationConfig rest.ImpersonationConfig) (permissions []PermissionT) {
+
+	namespace, name, isServiceAccount := kubernetes.ParseServiceAccountUserName(impersonationConfig.UserName)
+
+	switch {
+	case isServiceAccount:
+		permissions = append(permissions, PermissionT{
+			Resource: "serviceaccounts", ResourceName: name,
+			Namespace: namespace, Verb: VerbImpersonate,
+		})
+	case impersonationConfig.UserName != "":
+		permissions = append(permissions, PermissionT{
+			Resource: "users", ResourceName: impersonationConfig.UserName, Verb: VerbImpersonate,
+		})
+	}
+
+	for _, group := range impersonationConfig.Groups {
+		permissions = append(permissions, PermissionT{
+			Resource: "groups", ResourceName: group, Verb: VerbImpersonate,
+		})
+	}
+
+	return permissions
+}
+
+// getLookupPermissions return the permissions needed by the 'lookup' calls of a template
+func getLookupPermissions(templateString string) (permissions []PermissionT, warnings []string) {
+
+	if templateString == "" {
+		return permissions, warnings
+	}
+
+	lookupCalls, err := template.FindFunctionCalls(templateString, "lookup")
+	if err != nil {
+		return permissions, []string{fmt.Sprintf("error parsing template: %s", err.Error())}
+	}
+
+	for _, lookupCall := range lookupCalls {
+		arguments := lookupCall.Arguments
+
+		if len(arguments) != 4 || !lookupCall.Literal[0] || !lookupCall.Literal[1] {
+			warnings = append(warnings, "lookup with non-literal apiVersion or kind found. Its permissions must be added by hand")
+			continue
+		}
+
+		// Resources are guessed from kinds, as a cluster is not needed to generate the rules
+		gvr, _ := meta.UnsafeGuessKindToResource(schema.FromAPIVersionAndKind(arguments[0], arguments[1]))
+
+		// Literal empty name means listing. Non-literal namespace means any of them
+		verb := VerbGet
+		if lookupCall.Literal[3] && arguments[3] == "" {
+			verb = VerbList
+		}
+
+		permissions = append(permissions, PermissionT{
+			Group:     gvr.Group,
+			Resource:  gvr.Resource,
+			Namespace: arguments[2],
+			Verb:      verb,
+		})
+	}
+
+	return permissions, warnings
+}
+
+// GetPolicyRules converts a list of permissions into the minimal list of RBAC rules.
+// Namespaces are not taken into account, so rules are suitable for a ClusterRole or a Role
+func GetPolicyRules(permissions []PermissionT) (rules []rbacv1.PolicyRule) {
+
+	type ruleKey struct {
+		Group        string
+		Resource     string
+		ResourceName string
+	}
+
+	verbsByRule := map[ruleKey][]string{}
+	var ruleKeys []ruleKey
+
+	for _, permission := range permissions {
+		key := ruleKey{Group: permission.Group, Resource: permission.Resource, ResourceName: permission.ResourceName}
+
+		if _, found := verbsByRule[key]; !found {
+			ruleKeys = append(ruleKeys, key)
+		}
+
+		if !slices.Contains(verbsByRule[key], permission.Verb) {
+			verbsByRule[key] = append(verbsByRule[key], permission.Verb)
+		}
+	}
+
+	sort.Slice(ruleKeys, func(i, j int) bool {
+		return fmt.Sprint(ruleKeys[i]) < fmt.Sprint(ruleKeys[j])
+	})
+
+	for _, key := range ruleKeys {
+		verbs := verbsByRule[key]
+		sort.Strings(verbs)
+
+		rule := rbacv1.PolicyRule{
+			APIGroups: []string{key.Group},
+			Resources: []string{key.Resource},
+			Verbs:     verbs,
+		}
+
+		if key.ResourceName != "" {
+			rule.ResourceNames = []string{key.ResourceName}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
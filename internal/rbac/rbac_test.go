@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rbac
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/rules"
+)
+
+func TestImpersonationPermissions(t *testing.T) {
+	tests := []struct {
+		name            string
+		impersonate     v1alpha1.ImpersonateT
+		wantPermissions []PermissionT
+	}{
+		{
+			name: "serviceAccount",
+			impersonate: v1alpha1.ImpersonateT{
+				ServiceAccount: v1alpha1.ServiceAccountReferenceT{Namespace: "team-a", Name: "cleaner"},
+			},
+			wantPermissions: []PermissionT{
+				{Resource: "serviceaccounts", ResourceName: "cleaner", Namespace: "team-a", Verb: VerbImpersonate},
+			},
+		},
+		{
+			name: "serviceAccount with groups",
+			impersonate: v1alpha1.ImpersonateT{
+				ServiceAccount: v1alpha1.ServiceAccountReferenceT{Namespace: "team-a", Name: "cleaner"},
+				Groups:         []string{"cleaners"},
+			},
+			wantPermissions: []PermissionT{
+				{Resource: "serviceaccounts", ResourceName: "cleaner", Namespace: "team-a", Verb: VerbImpersonate},
+				{Resource: "groups", ResourceName: "cleaners", Verb: VerbImpersonate},
+			},
+		},
+		{
+			name:        "user with groups",
+			impersonate: v1alpha1.ImpersonateT{User: "jane", Groups: []string{"cleaners"}},
+			wantPermissions: []PermissionT{
+				{Resource: "users", ResourceName: "jane", Verb: VerbImpersonate},
+				{Resource: "groups", ResourceName: "cleaners", Verb: VerbImpersonate},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &v1alpha1.ConfigT{Spec: v1alpha1.SpecificationT{
+				Resources: []v1alpha1.ResourceT{{Name: "rule", Impersonate: test.impersonate}},
+			}}
+
+			permissions, _ := GetPermissions(config, "")
+			if !slices.Equal(permissions, test.wantPermissions) {
+				t.Fatalf("got permissions %v, want %v", permissions, test.wantPermissions)
+			}
+
+			// Every identity sent to Kubernetes must be granted, and nothing else
+			impersonationConfig, err := rules.GetImpersonationConfig(test.impersonate)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			sentIdentities := []string{"user:" + impersonationConfig.UserName}
+			for _, group := range impersonationConfig.Groups {
+				sentIdentities = append(sentIdentities, "group:"+group)
+			}
+
+			var grantedIdentities []string
+			for _, permission := range permissions {
+				switch permission.Resource {
+				case "serviceaccounts":
+					grantedIdentities = append(grantedIdentities,
+						fmt.Sprintf("user:system:serviceaccount:%s:%s", permission.Namespace, permission.ResourceName))
+				case "users":
+					grantedIdentities = append(grantedIdentities, "user:"+permission.ResourceName)
+				case "groups":
+					grantedIdentities = append(grantedIdentities, "group:"+permission.ResourceName)
+				}
+			}
+
+			if !slices.Equal(grantedIdentities, sentIdentities) {
+				t.Fatalf("granted %v, but %v are impersonated", grantedIdentities, sentIdentities)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	//
+	"k8s.io/client-go/rest"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+	"hitman/internal/kubernetes"
+)
+
+// GetName return the name used to identify a rule in logs, metrics and events.
+// Rules without name are identified by their position. When the rule comes from a Kubernetes object,
+// the object is part of the name
+func GetName(resourceIndex int, resource v1alpha1.ResourceT) string {
+	source := resource.CarriedSource
+
+	name := resource.Name
+	if name == "" {
+		name = fmt.Sprintf("resources[%d]", resourceIndex)
+		if source.Kind != "" {
+			name = fmt.Sprintf("resources[%d]", source.Index)
+		}
+	}
+
+	if source.Kind == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s/%s/%s", source.Kind, strings.TrimPrefix(source.Namespace+"/"+source.Name, "/"), name)
+}
+
+// IsEnabled return true when the rule must be processed
+func IsEnabled(resource v1alpha1.ResourceT) bool {
+	return resource.Enabled == nil || *resource.Enabled
+}
+
+// IsDryRun return true when the objects matched by the rule must not be touched.
+// The value of the rule wins when it is set, the global --dry-run flag is used otherwise
+func IsDryRun(resource v1alpha1.ResourceT) bool {
+	if resource.DryRun != nil {
+		return *resource.DryRun
+	}
+
+	return globals.ExecContext.DryRun
+}
+
+// IsImpersonating return true when the rule declares an identity to impersonate.
+// Empty fields (e.g. 'groups: []') do not declare anything
+func IsImpersonating(resource v1alpha1.ResourceT) bool {
+	impersonate := resource.Impersonate
+
+	return impersonate.User != "" || len(impersonate.Groups) > 0 ||
+		impersonate.ServiceAccount.Namespace != "" || impersonate.ServiceAccount.Name != ""
+}
+
+// GetImpersonationConfig return the config needed to impersonate the identity declared by a rule.
+// It is exactly what is sent to Kubernetes, so permissions to impersonate are computed from it too
+func GetImpersonationConfig(impersonate v1alpha1.ImpersonateT) (impersonationConfig rest.ImpersonationConfig, err error) {
+
+	serviceAccount := impersonate.ServiceAccount
+	serviceAccountDeclared := !reflect.ValueOf(serviceAccount).IsZero()
+
+	if impersonate.User != "" && serviceAccountDeclared {
+		return impersonationConfig, fmt.Errorf("impersonation can only declare one of user or serviceAccount")
+	}
+
+	if serviceAccountDeclared {
+		if serviceAccount.Namespace == "" || serviceAccount.Name == "" {
+			return impersonationConfig, fmt.Errorf("impersonated serviceAccount requires namespace and name")
+		}
+
+		impersonationConfig = kubernetes.GetServiceAccountImpersonationConfig(serviceAccount.Namespace, serviceAccount.Name)
+		if len(impersonate.Groups) > 0 {
+			impersonationConfig.Groups = impersonate.Groups
+		}
+		return impersonationConfig, nil
+	}
+
+	if impersonate.User == "" {
+		return impersonationConfig, fmt.Errorf("impersonated groups require a user")
+	}
+
+	return rest.ImpersonationConfig{
+		UserName: impersonate.User,
+		Groups:   impersonate.Groups,
+	}, nil
+}
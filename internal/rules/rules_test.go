@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"testing"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+)
+
+func TestIsDryRun(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name         string
+		globalDryRun bool
+		ruleDryRun   *bool
+		want         bool
+	}{
+		{"rule follows the flag when unset", false, nil, false},
+		{"rule follows --dry-run when unset", true, nil, true},
+		{"dryRun: true without --dry-run", false, &enabled, true},
+		{"dryRun: false with --dry-run", true, &disabled, false},
+		{"dryRun: true with --dry-run", true, &enabled, true},
+	}
+
+	defer func(dryRun bool) { globals.ExecContext.DryRun = dryRun }(globals.ExecContext.DryRun)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			globals.ExecContext.DryRun = test.globalDryRun
+
+			if got := IsDryRun(v1alpha1.ResourceT{DryRun: test.ruleDryRun}); got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
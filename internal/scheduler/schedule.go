@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	//
+	"github.com/robfig/cron/v3"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+var (
+	weekDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// intervalSchedule runs every fixed interval
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// getLocation return the timezone of a rule. Empty one means local time
+func getLocation(timezone string) (location *time.Location, err error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+
+	location, err = time.LoadLocation(timezone)
+	if err != nil {
+		return location, fmt.Errorf("unable to load timezone: %s", err.Error())
+	}
+
+	return location, nil
+}
+
+// ParseSchedule parses a rule schedule: an interval (e.g. 10m) or a cron expression (e.g. 0 2 * * *).
+// Cron descriptors such as @daily or @every are supported too.
+// When the schedule is empty, the synchronization time is used as interval
+func ParseSchedule(schedule string, timezone string, defaultInterval time.Duration) (result cron.Schedule, err error) {
+
+	if schedule == "" {
+		if defaultInterval <= 0 {
+			return result, fmt.Errorf("synchronization time must be greater than zero")
+		}
+		return intervalSchedule{interval: defaultInterval}, nil
+	}
+
+	interval, err := time.ParseDuration(schedule)
+	if err == nil {
+		if interval <= 0 {
+			return result, fmt.Errorf("schedule interval must be greater than zero")
+		}
+		return intervalSchedule{interval: interval}, nil
+	}
+
+	location, err := getLocation(timezone)
+	if err != nil {
+		return result, err
+	}
+
+	cronSchedule, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return result, fmt.Errorf("schedule is neither an interval nor a cron expression: %s", err.Error())
+	}
+
+	// Timezone is only applied when the expression does not define its own one with CRON_TZ
+	specSchedule, ok := cronSchedule.(*cron.SpecSchedule)
+	if ok && !strings.HasPrefix(schedule, "CRON_TZ=") && !strings.HasPrefix(schedule, "TZ=") {
+		specSchedule.Location = location
+	}
+
+	return cronSchedule, nil
+}
+
+// parseClock parses a time of the day expressed as HH:MM, and return it as the time elapsed since midnight
+func parseClock(clock string) (elapsed time.Duration, err error) {
+	parsedClock, err := time.Parse("15:04", clock)
+	if err != nil {
+		return elapsed, fmt.Errorf("time '%s' must be expressed as HH:MM", clock)
+	}
+
+	return time.Duration(parsedClock.Hour())*time.Hour + time.Duration(parsedClock.Minute())*time.Minute, nil
+}
+
+// ValidateWindow checks the days and times of a window
+func ValidateWindow(window v1alpha1.WindowT) (err error) {
+	for _, day := range window.Days {
+		if !slices.Contains(weekDays, strings.ToLower(day)) {
+			return fmt.Errorf("day '%s' must be one of: Mon, Tue, Wed, Thu, Fri, Sat, Sun", day)
+		}
+	}
+
+	start, err := parseClock(window.Start)
+	if err != nil {
+		return err
+	}
+
+	end, err := parseClock(window.End)
+	if err != nil {
+		return err
+	}
+
+	// The end is excluded, so such a window would never be open
+	if start == end {
+		return fmt.Errorf("window start and end must be different, as the end is not included")
+	}
+
+	return nil
+}
+
+// isInsideWindow return true when given time is inside the window.
+// Windows crossing midnight belong to the day they start
+func isInsideWindow(window v1alpha1.WindowT, t time.Time) (inside bool, err error) {
+	start, err := parseClock(window.Start)
+	if err != nil {
+		return false, err
+	}
+
+	end, err := parseClock(window.End)
+	if err != nil {
+		return false, err
+	}
+
+	isDayIncluded := func(day time.Weekday) bool {
+		if len(window.Days) == 0 {
+			return true
+		}
+		return slices.ContainsFunc(window.Days, func(windowDay string) bool {
+			return strings.ToLower(windowDay) == weekDays[day]
+		})
+	}
+
+	elapsed := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+
+	if start <= end {
+		return isDayIncluded(t.Weekday()) && elapsed >= start && elapsed < end, nil
+	}
+
+	// Window crosses midnight
+	previousDay := (t.Weekday() + 6) % 7
+	return (isDayIncluded(t.Weekday()) && elapsed >= start) || (isDayIncluded(previousDay) && elapsed < end), nil
+}
+
+// IsAllowed return true when a rule can run at given time according to its windows.
+// Rules without active windows are always active
+func IsAllowed(resource v1alpha1.ResourceT, t time.Time) (allowed bool, err error) {
+	location, err := getLocation(resource.Timezone)
+	if err != nil {
+		return false, err
+	}
+	t = t.In(location)
+
+	for _, window := range resource.BlackoutWindows {
+		inside, err := isInsideWindow(window, t)
+		if err != nil || inside {
+			return false, err
+		}
+	}
+
+	if len(resource.ActiveWindows) == 0 {
+		return true, nil
+	}
+
+	for _, window := range resource.ActiveWindows {
+		inside, err := isInsideWindow(window, t)
+		if err != nil || inside {
+			return inside, err
+		}
+	}
+
+	return false, nil
+}
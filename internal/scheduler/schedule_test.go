@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Monday
+	from := time.Date(2026, 1, 5, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		schedule        string
+		timezone        string
+		defaultInterval time.Duration
+		wantNext        time.Time
+		wantError       string
+	}{
+		{"empty uses the synchronization time", "", "", time.Minute, from.Add(time.Minute), ""},
+		{"empty without synchronization time", "", "", 0, time.Time{}, "synchronization time must be greater than zero"},
+		{"interval", "10m", "", time.Minute, from.Add(10 * time.Minute), ""},
+		{"negative interval", "-10m", "", time.Minute, time.Time{}, "schedule interval must be greater than zero"},
+		{"cron", "0 2 * * *", "UTC", time.Minute, time.Date(2026, 1, 6, 2, 0, 0, 0, time.UTC), ""},
+		{"cron in timezone", "0 2 * * *", "Europe/Madrid", time.Minute, time.Date(2026, 1, 6, 1, 0, 0, 0, time.UTC), ""},
+		{"cron with its own timezone", "CRON_TZ=UTC 0 2 * * *", "Europe/Madrid", time.Minute, time.Date(2026, 1, 6, 2, 0, 0, 0, time.UTC), ""},
+		{"descriptor", "@hourly", "UTC", time.Minute, time.Date(2026, 1, 5, 11, 0, 0, 0, time.UTC), ""},
+		{"every descriptor", "@every 5m", "UTC", time.Minute, from.Add(5 * time.Minute), ""},
+		{"invalid cron", "0 2 * *", "UTC", time.Minute, time.Time{}, "neither an interval nor a cron expression"},
+		{"invalid timezone", "0 2 * * *", "Mars/Olympus", time.Minute, time.Time{}, "unable to load timezone"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(test.schedule, test.timezone, test.defaultInterval)
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if next := schedule.Next(from); !next.Equal(test.wantNext) {
+				t.Fatalf("got next run %s, want %s", next, test.wantNext)
+			}
+		})
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		window    v1alpha1.WindowT
+		wantError string
+	}{
+		{"valid", v1alpha1.WindowT{Days: []string{"Mon", "fri"}, Start: "09:00", End: "17:30"}, ""},
+		{"without days", v1alpha1.WindowT{Start: "22:00", End: "06:00"}, ""},
+		{"invalid day", v1alpha1.WindowT{Days: []string{"Monday"}, Start: "09:00", End: "17:00"}, "day 'Monday' must be one of"},
+		{"invalid start", v1alpha1.WindowT{Start: "9am", End: "17:00"}, "time '9am' must be expressed as HH:MM"},
+		{"missing end", v1alpha1.WindowT{Start: "09:00"}, "time '' must be expressed as HH:MM"},
+		{"empty window", v1alpha1.WindowT{Start: "09:00", End: "09:00"}, "window start and end must be different"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateWindow(test.window)
+			if test.wantError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if test.wantError != "" && (err == nil || !strings.Contains(err.Error(), test.wantError)) {
+				t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+			}
+		})
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	workingHours := v1alpha1.WindowT{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "09:00", End: "17:00"}
+	fridayNight := v1alpha1.WindowT{Days: []string{"Fri"}, Start: "22:00", End: "06:00"}
+
+	tests := []struct {
+		name     string
+		resource v1alpha1.ResourceT
+		at       time.Time
+		want     bool
+	}{
+		{"without windows", v1alpha1.ResourceT{}, time.Date(2026, 1, 4, 3, 0, 0, 0, time.UTC), true},
+		{"inside active window", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{workingHours}},
+			time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), true},
+		{"end of active window is excluded", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{workingHours}},
+			time.Date(2026, 1, 5, 17, 0, 0, 0, time.UTC), false},
+		{"active window on another day", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{workingHours}},
+			time.Date(2026, 1, 4, 10, 0, 0, 0, time.UTC), false},
+		{"active window in timezone", v1alpha1.ResourceT{Timezone: "America/New_York", ActiveWindows: []v1alpha1.WindowT{workingHours}},
+			time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), false},
+		{"window crossing midnight, before it", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{fridayNight}},
+			time.Date(2026, 1, 9, 23, 0, 0, 0, time.UTC), true},
+		{"window crossing midnight, after it", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{fridayNight}},
+			time.Date(2026, 1, 10, 5, 59, 0, 0, time.UTC), true},
+		{"window crossing midnight belongs to the day it starts", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{fridayNight}},
+			time.Date(2026, 1, 9, 5, 0, 0, 0, time.UTC), false},
+		{"blackout wins over active", v1alpha1.ResourceT{ActiveWindows: []v1alpha1.WindowT{workingHours},
+			BlackoutWindows: []v1alpha1.WindowT{{Start: "12:00", End: "13:00"}}},
+			time.Date(2026, 1, 5, 12, 30, 0, 0, time.UTC), false},
+		{"outside blackout", v1alpha1.ResourceT{BlackoutWindows: []v1alpha1.WindowT{{Start: "12:00", End: "13:00"}}},
+			time.Date(2026, 1, 5, 13, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Windows are in local time by default, which depends on the machine
+			if test.resource.Timezone == "" {
+				test.resource.Timezone = "UTC"
+			}
+
+			allowed, err := IsAllowed(test.resource, test.at)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if allowed != test.want {
+				t.Fatalf("got %t, want %t", allowed, test.want)
+			}
+		})
+	}
+}
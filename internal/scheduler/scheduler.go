@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	//
+	"github.com/robfig/cron/v3"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/internal/globals"
+	"hitman/internal/rules"
+)
+
+// ruleStateT defines the scheduling state of a rule
+type ruleStateT struct {
+	schedule cron.Schedule
+	nextRun  time.Time
+}
+
+// Scheduler decides which rules must run on each moment, according to their schedules and windows.
+// Rules run for the first time as soon as they are known
+type Scheduler struct {
+	rules map[string]*ruleStateT
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		rules: make(map[string]*ruleStateT),
+	}
+}
+
+// getRuleKey return the key identifying a rule between config reloads.
+// Changing the schedule of a rule (or the synchronization time it defaults to) makes it run again immediately
+func getRuleKey(resourceIndex int, resource v1alpha1.ResourceT, defaultInterval time.Duration) string {
+	schedule := resource.Schedule
+	if schedule == "" {
+		schedule = defaultInterval.String()
+	}
+
+	return fmt.Sprintf("%s/%s/%s", rules.GetName(resourceIndex, resource), schedule, resource.Timezone)
+}
+
+// GetDueResources return the indexes of the resources that must run at given time, and schedules their next runs.
+// Due rules outside their windows are skipped until their next run
+func (s *Scheduler) GetDueResources(config *v1alpha1.ConfigT, now time.Time) (dueResourceIndexes map[int]bool) {
+
+	dueResourceIndexes = make(map[int]bool)
+	currentRules := make(map[string]*ruleStateT, len(config.Spec.Resources))
+
+	for resourceIndex, resource := range config.Spec.Resources {
+		if !rules.IsEnabled(resource) {
+			continue
+		}
+
+		ruleKey := getRuleKey(resourceIndex, resource, config.Spec.Synchronization.CarriedTime)
+
+		ruleState, found := s.rules[ruleKey]
+		if !found {
+			schedule, err := ParseSchedule(resource.Schedule, resource.Timezone, config.Spec.Synchronization.CarriedTime)
+			if err != nil {
+				globals.ExecContext.Logger.Infow("error parsing schedule. Skipping rule",
+					"resourceIndex", resourceIndex, "error", err.Error())
+				continue
+			}
+
+			ruleState = &ruleStateT{schedule: schedule, nextRun: now}
+		}
+		currentRules[ruleKey] = ruleState
+
+		if now.Before(ruleState.nextRun) {
+			continue
+		}
+		ruleState.nextRun = ruleState.schedule.Next(now)
+
+		allowed, err := IsAllowed(resource, now)
+		if err != nil {
+			globals.ExecContext.Logger.Infow("error checking windows. Skipping rule",
+				"rule", rules.GetName(resourceIndex, resource), "error", err.Error())
+			continue
+		}
+
+		if !allowed {
+			globals.ExecContext.Logger.Debugw("rule is outside its windows. Skipping until next run",
+				"rule", rules.GetName(resourceIndex, resource), "nextRun", ruleState.nextRun.Format(time.RFC3339))
+			continue
+		}
+
+		dueResourceIndexes[resourceIndex] = true
+	}
+
+	// Rules removed from the config are forgotten
+	s.rules = currentRules
+
+	return dueResourceIndexes
+}
+
+// GetNextRun return the earliest time when any rule must run
+func (s *Scheduler) GetNextRun() (nextRun time.Time) {
+	for _, ruleState := range s.rules {
+		if nextRun.IsZero() || ruleState.nextRun.Before(nextRun) {
+			nextRun = ruleState.nextRun
+		}
+	}
+	return nextRun
+}
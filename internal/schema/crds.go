@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"reflect"
+	"strings"
+
+	//
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	//
+	"hitman/api/v1alpha1"
+)
+
+// GetCustomResourceDefinitions return the CRDs for the Kubernetes APIs served by Hitman.
+// Their schemas are generated from the types defined in 'api' package, so they never diverge
+func GetCustomResourceDefinitions() []apiextensionsv1.CustomResourceDefinition {
+	return []apiextensionsv1.CustomResourceDefinition{
+		getCustomResourceDefinition(
+			v1alpha1.KindHitman, v1alpha1.ResourceHitman, []string{"hm"},
+			apiextensionsv1.ClusterScoped,
+			reflect.TypeOf(v1alpha1.SpecificationT{}),
+		),
+		getCustomResourceDefinition(
+			v1alpha1.KindHitmanPolicy, v1alpha1.ResourceHitmanPolicy, []string{"hmp"},
+			apiextensionsv1.NamespaceScoped,
+			reflect.TypeOf(v1alpha1.PolicySpecificationT{}),
+		),
+	}
+}
+
+// getCustomResourceDefinition return a CRD for a kind whose spec is described by given type.
+// All of them share the same status, and expose it as a subresource
+func getCustomResourceDefinition(kind, plural string, shortNames []string,
+	scope apiextensionsv1.ResourceScope, specType reflect.Type) apiextensionsv1.CustomResourceDefinition {
+
+	openAPISchema := apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			"metadata":   {Type: "object"},
+			"spec":       GetOpenAPISchema(specType),
+			"status":     GetOpenAPISchema(reflect.TypeOf(v1alpha1.StatusT{})),
+		},
+	}
+
+	return apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: plural + "." + v1alpha1.Group,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: v1alpha1.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:       kind,
+				ListKind:   kind + "List",
+				Plural:     plural,
+				Singular:   strings.ToLower(kind),
+				ShortNames: shortNames,
+			},
+			Scope: scope,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    v1alpha1.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &openAPISchema,
+					},
+					Subresources: &apiextensionsv1.CustomResourceSubresources{
+						Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{
+							Name:     "Last Run",
+							Type:     "string",
+							JSONPath: ".status.lastRun",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"unicode"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/api/v1alpha2"
+)
+
+// descriptions are the doc comments of the API types and their fields,
+// indexed by '<package path>.<type>' and '<package path>.<type>.<field>'
+var descriptions = map[string]string{}
+
+func init() {
+	apiSources := map[string]string{
+		reflect.TypeOf(v1alpha1.ConfigT{}).PkgPath(): v1alpha1.TypesSource,
+		reflect.TypeOf(v1alpha2.ConfigT{}).PkgPath(): v1alpha2.TypesSource,
+	}
+
+	for pkgPath, source := range apiSources {
+		err := parseDescriptions(pkgPath, source)
+		if err != nil {
+			panic("impossible to parse API types source: " + err.Error())
+		}
+	}
+}
+
+// parseDescriptions read the doc comments of the struct types defined in given source code
+func parseDescriptions(pkgPath, source string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	for _, declaration := range file.Decls {
+		genDeclaration, ok := declaration.(*ast.GenDecl)
+		if !ok || genDeclaration.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDeclaration.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			typeKey := pkgPath + "." + typeSpec.Name.Name
+			typeDoc := genDeclaration.Doc
+			if typeSpec.Doc != nil {
+				typeDoc = typeSpec.Doc
+			}
+			descriptions[typeKey] = cleanTypeDescription(typeSpec.Name.Name, typeDoc.Text())
+
+			for _, field := range structType.Fields.List {
+				fieldDoc := field.Doc
+				if fieldDoc == nil {
+					fieldDoc = field.Comment
+				}
+
+				for _, fieldName := range field.Names {
+					descriptions[typeKey+"."+fieldName.Name] = cleanDescription(fieldDoc.Text())
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// cleanDescription return a doc comment ready to be shown to users.
+// Comments still to be written are discarded
+func cleanDescription(description string) string {
+	description = strings.TrimSpace(description)
+	if strings.Contains(description, "TODO") {
+		return ""
+	}
+
+	return description
+}
+
+// cleanTypeDescription return the doc comment of a type without its Go name, as users never see it.
+// For example: 'ClusterT defines a Kubernetes cluster' is returned as 'A Kubernetes cluster'
+func cleanTypeDescription(typeName, description string) string {
+	description = cleanDescription(description)
+
+	description, found := strings.CutPrefix(description, typeName+" defines ")
+	if !found || description == "" {
+		return description
+	}
+
+	runes := []rune(description)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// getTypeDescription return the description of a type defined in the API
+func getTypeDescription(t reflect.Type) string {
+	return descriptions[t.PkgPath()+"."+t.Name()]
+}
+
+// getFieldDescription return the description of a field of a type defined in the API.
+// When the field is not documented, the description of its type is used. Items of lists already carry it
+func getFieldDescription(t reflect.Type, field reflect.StructField) string {
+	description := descriptions[t.PkgPath()+"."+t.Name()+"."+field.Name]
+	if description != "" {
+		return description
+	}
+
+	fieldType := field.Type
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	return getTypeDescription(fieldType)
+}
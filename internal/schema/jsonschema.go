@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	//
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/api/v1alpha2"
+)
+
+const (
+	// JSONSchemaDraft is the JSON Schema version of the generated schemas.
+	// Draft 7 is the newest one understood by yaml-language-server and Helm
+	JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	// expressionPattern matches values made of '${...}' expressions, expanded when config files are read
+	expressionPattern = `^(\$\{[^}]+\})+$`
+)
+
+var (
+	// configTypes are the types of config files for each supported apiVersion
+	configTypes = map[string]reflect.Type{
+		v1alpha1.Group + "/" + v1alpha1.Version: reflect.TypeOf(v1alpha1.ConfigT{}),
+		v1alpha2.Group + "/" + v1alpha2.Version: reflect.TypeOf(v1alpha2.ConfigT{}),
+	}
+)
+
+// GetConfigJSONSchema return a JSON Schema for config files written in given apiVersion.
+// It is as strict as Hitman itself when reading them, so unknown fields are not allowed
+func GetConfigJSONSchema(apiVersion string) (jsonSchema apiextensionsv1.JSONSchemaProps, err error) {
+	configType, found := configTypes[apiVersion]
+	if !found {
+		return jsonSchema, fmt.Errorf("apiVersion '%s' is not supported", apiVersion)
+	}
+
+	jsonSchema = GetOpenAPISchema(configType)
+	jsonSchema.Schema = JSONSchemaDraft
+	jsonSchema.Title = fmt.Sprintf("Hitman config (%s)", apiVersion)
+
+	// Documents without apiVersion and kind are accepted, as included files inherit them
+	apiVersionSchema := jsonSchema.Properties["apiVersion"]
+	apiVersionSchema.Enum = []apiextensionsv1.JSON{getJSONValue(apiVersion)}
+	jsonSchema.Properties["apiVersion"] = apiVersionSchema
+
+	kindSchema := jsonSchema.Properties["kind"]
+	kindSchema.Enum = []apiextensionsv1.JSON{getJSONValue(v1alpha1.KindHitman)}
+	jsonSchema.Properties["kind"] = kindSchema
+
+	adaptToConfigFiles(&jsonSchema)
+	return jsonSchema, nil
+}
+
+// GetChartValuesJSONSchema return a JSON Schema for the values of the Helm chart.
+// Only the config is described. It can be written as a YAML object in any supported apiVersion,
+// or as a string for compatibility
+func GetChartValuesJSONSchema() (jsonSchema apiextensionsv1.JSONSchemaProps, err error) {
+
+	// Configs without 'rules' nor 'resources' are valid in several versions, so any of them is enough
+	configSchemas := []apiextensionsv1.JSONSchemaProps{{Type: "string"}}
+
+	var apiVersions []string
+	for apiVersion := range configTypes {
+		apiVersions = append(apiVersions, apiVersion)
+	}
+	slices.Sort(apiVersions)
+
+	for _, apiVersion := range apiVersions {
+		configSchema, err := GetConfigJSONSchema(apiVersion)
+		if err != nil {
+			return jsonSchema, err
+		}
+		configSchema.Schema = ""
+
+		configSchemas = append(configSchemas, configSchema)
+	}
+
+	jsonSchema = apiextensionsv1.JSONSchemaProps{
+		Schema: JSONSchemaDraft,
+		Title:  "Hitman chart values",
+		Type:   "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"agent": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"configSource": {
+						Description: "Where the config is read from: 'file' or 'kubernetes'",
+						Type:        "string",
+						Enum:        []apiextensionsv1.JSON{getJSONValue("file"), getJSONValue("kubernetes")},
+					},
+					"config": {
+						Description: "Config of Hitman, used when configSource is 'file'",
+						AnyOf:       configSchemas,
+					},
+				},
+			},
+		},
+	}
+
+	return jsonSchema, nil
+}
+
+// adaptToConfigFiles adapt given schema, recursively, to the way config files are decoded.
+// Properties not described in objects are rejected. Maps are not affected, as their keys are free.
+// Any scalar is accepted where a string is expected, as YAML decodes values like 'true' or '5' into strings.
+// Numbers and booleans can be written as '${...}' expressions too, as they are expanded before decoding
+func adaptToConfigFiles(jsonSchema *apiextensionsv1.JSONSchemaProps) {
+	switch {
+	case jsonSchema.Type == "string" && len(jsonSchema.Enum) == 0:
+		jsonSchema.Type = ""
+		jsonSchema.AnyOf = []apiextensionsv1.JSONSchemaProps{
+			{Type: "string"}, {Type: "number"}, {Type: "boolean"},
+		}
+
+	case jsonSchema.Type == "integer" || jsonSchema.Type == "number" || jsonSchema.Type == "boolean":
+		jsonSchema.AnyOf = []apiextensionsv1.JSONSchemaProps{
+			{Type: jsonSchema.Type}, {Type: "string", Pattern: expressionPattern},
+		}
+		jsonSchema.Type = ""
+	}
+
+	if jsonSchema.Properties != nil {
+		jsonSchema.AdditionalProperties = &apiextensionsv1.JSONSchemaPropsOrBool{Allows: false}
+
+		for propertyName, propertySchema := range jsonSchema.Properties {
+			adaptToConfigFiles(&propertySchema)
+			jsonSchema.Properties[propertyName] = propertySchema
+		}
+	}
+
+	if jsonSchema.Items != nil && jsonSchema.Items.Schema != nil {
+		adaptToConfigFiles(jsonSchema.Items.Schema)
+	}
+
+	if jsonSchema.AdditionalProperties != nil && jsonSchema.AdditionalProperties.Schema != nil {
+		adaptToConfigFiles(jsonSchema.AdditionalProperties.Schema)
+	}
+}
+
+// getJSONValue return given value encoded as JSON, as used in enums
+func getJSONValue(value interface{}) apiextensionsv1.JSON {
+	valueBytes, _ := json.Marshal(value)
+	return apiextensionsv1.JSON{Raw: valueBytes}
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"slices"
+	"testing"
+
+	//
+	"hitman/api/v1alpha1"
+	"hitman/api/v1alpha2"
+)
+
+func TestGetChartValuesJSONSchema(t *testing.T) {
+	jsonSchema, err := GetChartValuesJSONSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	configSchema := jsonSchema.Properties["agent"].Properties["config"]
+
+	var describedTypes []string
+	for _, alternative := range configSchema.AnyOf {
+		if alternative.Type == "string" {
+			describedTypes = append(describedTypes, "string")
+			continue
+		}
+
+		for _, apiVersion := range alternative.Properties["apiVersion"].Enum {
+			describedTypes = append(describedTypes, string(apiVersion.Raw))
+		}
+	}
+
+	wantTypes := []string{
+		"string",
+		`"` + v1alpha1.Group + "/" + v1alpha1.Version + `"`,
+		`"` + v1alpha2.Group + "/" + v1alpha2.Version + `"`,
+	}
+
+	if !slices.Equal(describedTypes, wantTypes) {
+		t.Fatalf("got config described as %v, want %v", describedTypes, wantTypes)
+	}
+
+	// Configs valid in several versions must not be rejected, as it would happen with oneOf
+	if len(configSchema.OneOf) > 0 {
+		t.Fatalf("got config described with oneOf, want anyOf")
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"reflect"
+	"strings"
+
+	//
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// GetOpenAPISchema return an OpenAPI v3 schema for the given type.
+// Properties are named after the YAML tags of the fields, as those are the ones used in Hitman's config.
+// Fields tagged with '-' are considered internal and not included. Doc comments are used as descriptions
+func GetOpenAPISchema(t reflect.Type) apiextensionsv1.JSONSchemaProps {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return apiextensionsv1.JSONSchemaProps{Type: "string"}
+
+	case reflect.Bool:
+		return apiextensionsv1.JSONSchemaProps{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return apiextensionsv1.JSONSchemaProps{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return apiextensionsv1.JSONSchemaProps{Type: "number"}
+
+	case reflect.Slice, reflect.Array:
+		itemsSchema := GetOpenAPISchema(t.Elem())
+		return apiextensionsv1.JSONSchemaProps{
+			Type: "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: &itemsSchema,
+			},
+		}
+
+	case reflect.Map:
+		valuesSchema := GetOpenAPISchema(t.Elem())
+		return apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+				Allows: true,
+				Schema: &valuesSchema,
+			},
+		}
+
+	case reflect.Struct:
+		return getStructOpenAPISchema(t)
+	}
+
+	// Anything else can not be described, so it is accepted as it comes
+	preserveUnknownFields := true
+	return apiextensionsv1.JSONSchemaProps{
+		XPreserveUnknownFields: &preserveUnknownFields,
+	}
+}
+
+// getStructOpenAPISchema return an OpenAPI v3 schema for a struct type
+func getStructOpenAPISchema(t reflect.Type) apiextensionsv1.JSONSchemaProps {
+	structSchema := apiextensionsv1.JSONSchemaProps{
+		Type:        "object",
+		Description: getTypeDescription(t),
+		Properties:  map[string]apiextensionsv1.JSONSchemaProps{},
+	}
+
+	for fieldIndex := 0; fieldIndex < t.NumField(); fieldIndex++ {
+		field := t.Field(fieldIndex)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldName := getFieldName(field)
+		if fieldName == "" {
+			continue
+		}
+
+		fieldSchema := GetOpenAPISchema(field.Type)
+		fieldSchema.Description = getFieldDescription(t, field)
+		structSchema.Properties[fieldName] = fieldSchema
+	}
+
+	return structSchema
+}
+
+// getFieldName return the name of a field as written in YAML.
+// It returns an empty string when the field is not intended to be written
+func getFieldName(field reflect.StructField) string {
+	yamlTag, found := field.Tag.Lookup("yaml")
+	if !found {
+		return strings.ToLower(field.Name)
+	}
+
+	fieldName, _, _ := strings.Cut(yamlTag, ",")
+	if fieldName == "-" {
+		return ""
+	}
+
+	if fieldName == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return fieldName
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// clock is the source of time used by time-related template functions such as 'now'.
+	// It defaults to the wall clock, but it can be replaced to make evaluations reproducible
+	clock      = time.Now
+	clockMutex sync.RWMutex
+)
+
+// SetClock replaces the source of time used by the templating system.
+// Passing nil restores the wall clock
+func SetClock(newClock func() time.Time) {
+	clockMutex.Lock()
+	defer clockMutex.Unlock()
+
+	if newClock == nil {
+		newClock = time.Now
+	}
+	clock = newClock
+}
+
+// SetFixedClock freezes the source of time used by the templating system at the given instant.
+// It covers 'now', 'ago', 'age', 'ageOf' and 'olderThan', and the quarantine of processing loops.
+// Sprig functions taking their dates as arguments are not affected, but the ones generating
+// certificates (genCA, genSelfSignedCert...) keep using the wall clock for their validity
+func SetFixedClock(instant time.Time) {
+	SetClock(func() time.Time {
+		return instant
+	})
+}
+
+// Now return the current time according to the configured clock
+func Now() time.Time {
+	clockMutex.RLock()
+	defer clockMutex.RUnlock()
+
+	return clock()
+}
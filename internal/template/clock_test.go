@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetFixedClock(t *testing.T) {
+	instant := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	SetFixedClock(instant)
+	defer SetClock(nil)
+
+	if got := Now(); !got.Equal(instant) {
+		t.Fatalf("Now() = %s, want %s", got, instant)
+	}
+
+	result, err := EvaluateTemplate(`{{ dateInZone "2006-01-02T15:04:05Z07:00" now "UTC" }}`, &map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result != "2026-01-01T00:00:00Z" {
+		t.Fatalf("now = %s, want the frozen instant", result)
+	}
+}
+
+func TestSetClockNilRestoresWallClock(t *testing.T) {
+	SetFixedClock(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(nil)
+
+	if time.Since(Now()) > time.Minute {
+		t.Fatalf("Now() = %s, want the wall clock", Now())
+	}
+}
+
+func TestClockFunctions(t *testing.T) {
+	instant := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// The live clock must not be used when an instant is passed
+	SetFixedClock(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer SetClock(nil)
+
+	object := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"creationTimestamp": "2026-01-01T10:00:00Z",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"now", `{{ dateInZone "15:04" now "UTC" }}`, "12:00"},
+		{"now matches .now", `{{ eq (now | unixEpoch) (.now | unixEpoch) }}`, "true"},
+		{"ago", `{{ ago 1767265200 }}`, "1h0m0s"},
+		{"ago of now", `{{ ago now }}`, "0s"},
+		{"age", `{{ (age .object).Hours }}`, "2"},
+		{"ageOf string", `{{ (ageOf "2026-01-01T11:30:00Z").Minutes }}`, "30"},
+		{"ageOf epoch", `{{ (ageOf 1767265200).Hours }}`, "1"},
+		{"olderThan true", `{{ .object | olderThan "1h" }}`, "true"},
+		{"olderThan false", `{{ .object | olderThan "3h" }}`, "false"},
+		{"age without timestamp", `{{ age (dict) }}`, "0s"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := &map[string]interface{}{"now": instant, "object": object}
+
+			result, err := EvaluateTemplate(test.template, data, GetClockFunctions(instant))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if result != test.want {
+				t.Fatalf("got %s, want %s", result, test.want)
+			}
+		})
+	}
+}
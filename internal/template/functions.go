@@ -6,13 +6,16 @@ package template
 import (
 	"bytes"
 	"encoding/json"
-	"log"
+	"fmt"
 	"strings"
 	"text/template"
 
 	"github.com/BurntSushi/toml"
 	"github.com/Masterminds/sprig/v3"
+	"go.uber.org/zap"
 	"sigs.k8s.io/yaml"
+
+	"hitman/internal/globals"
 )
 
 // FOLKS, ATTENTION HERE:
@@ -21,7 +24,10 @@ import (
 // for people who are already comfortable with Helm. Not all the extra functionality was added to keep this simpler.
 // Ref: https://github.com/helm/helm/blob/main/pkg/engine/funcs.go
 
-func EvaluateTemplate(templateString string, data *map[string]interface{}) (result string, err error) {
+// EvaluateTemplate evaluates a template string injecting the given data.
+// Extra functions can be passed to extend the default ones. This is useful for those functions that need
+// some context from the caller, such as a Kubernetes client
+func EvaluateTemplate(templateString string, data *map[string]interface{}, extraFunctions ...template.FuncMap) (result string, err error) {
 
 	// setVar function is defined as clojure to intercept 'data'
 	// done this way as 'data' being passed as func param in later func map is not convenient
@@ -42,8 +48,21 @@ func EvaluateTemplate(templateString string, data *map[string]interface{}) (resu
 	templateFunctionsMap := GetFunctionsMap()
 	templateFunctionsMap["setVar"] = setVar
 
-	// Create a Template object from the given string
-	parsedTemplate, err := template.New("main").Funcs(templateFunctionsMap).Parse(templateString)
+	for _, functions := range extraFunctions {
+		for functionName, function := range functions {
+			templateFunctionsMap[functionName] = function
+		}
+	}
+
+	// Create a Template object from the given string. Named snippets are callable from it with 'include'
+	parsedTemplate := template.New("main").Funcs(templateFunctionsMap)
+
+	err = addSnippets(parsedTemplate)
+	if err != nil {
+		return result, err
+	}
+
+	parsedTemplate, err = parsedTemplate.Parse(templateString)
 	if err != nil {
 		return result, err
 	}
@@ -78,8 +97,13 @@ func GetFunctionsMap() template.FuncMap {
 		"fromJson":      fromJSON,
 		"fromJsonArray": fromJSONArray,
 
+		// Kubernetes-aware functions
+		"parseQuantity":   parseQuantity,
+		"compareQuantity": compareQuantity,
+		"hasCondition":    hasCondition,
+
 		// Extended funcs
-		"logPrintf": logPrintf,
+		"logPrintf": NewLogPrintf(nil),
 		//"setVar":    func(string, interface{}) string,
 	}
 
@@ -87,6 +111,12 @@ func GetFunctionsMap() template.FuncMap {
 		f[k] = v
 	}
 
+	// Time is taken from an injectable clock to make evaluations reproducible.
+	// Callers evaluating several templates at once should pass their own instant with GetClockFunctions
+	for k, v := range GetClockFunctions(Now()) {
+		f[k] = v
+	}
+
 	return f
 }
 
@@ -103,16 +133,23 @@ func toYAML(v interface{}) string {
 	return strings.TrimSuffix(string(data), "\n")
 }
 
-// fromYAML converts a YAML document into a map[string]interface{}.
-//
-// logPrintf is the equivalent of printf function.
-// It take a format-string and several items as arguments and throw the result by logs.
+// NewLogPrintf return a function that is the equivalent of printf function.
+// It take a format-string and several items as arguments and throw the result by given logger.
+// When no logger is given, the global one is used.
 // It returns an empty string as returning something is required in Go template's func mapping
-func logPrintf(format string, v ...interface{}) string {
-	log.Printf(format, v...)
-	return ""
+func NewLogPrintf(logger *zap.SugaredLogger) func(format string, v ...interface{}) string {
+	return func(format string, v ...interface{}) string {
+		if logger == nil {
+			logger = &globals.ExecContext.Logger
+		}
+
+		logger.Infow(fmt.Sprintf(format, v...), "source", "template")
+		return ""
+	}
 }
 
+// fromYAML converts a YAML document into a map[string]interface{}.
+//
 // This is not a general-purpose YAML parser, and will not parse all valid
 // YAML documents. Additionally, because its intended use is within templates
 // it tolerates errors. It will insert the returned error message string into
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"text/template/parse"
+)
+
+// FunctionCallT defines a call to a function found inside a template
+type FunctionCallT struct {
+	// Only literal string arguments can be known without evaluating the template, so the rest are empty
+	Arguments []string
+	Literal   []bool
+}
+
+// Validate checks the syntax of a template without evaluating it.
+// Functions are not checked, as some of them are only defined on evaluation time
+func Validate(templateString string) (err error) {
+	tree := parse.New("main")
+	tree.Mode = parse.SkipFuncCheck
+
+	_, err = tree.Parse(templateString, "", "", map[string]*parse.Tree{})
+	return err
+}
+
+// FindFunctionCalls return every call to the given function inside a template.
+// This is useful to know what a template will do before evaluating it
+func FindFunctionCalls(templateString string, functionName string) (calls []FunctionCallT, err error) {
+
+	// Functions are not checked, as some of them are only defined on evaluation time
+	tree := parse.New("main")
+	tree.Mode = parse.SkipFuncCheck
+
+	_, err = tree.Parse(templateString, "", "", map[string]*parse.Tree{})
+	if err != nil {
+		return calls, err
+	}
+
+	walkNode(tree.Root, func(command *parse.CommandNode) {
+		if len(command.Args) == 0 {
+			return
+		}
+
+		identifier, ok := command.Args[0].(*parse.IdentifierNode)
+		if !ok || identifier.Ident != functionName {
+			return
+		}
+
+		call := FunctionCallT{}
+		for _, argument := range command.Args[1:] {
+			stringArgument, ok := argument.(*parse.StringNode)
+			if !ok {
+				call.Arguments = append(call.Arguments, "")
+				call.Literal = append(call.Literal, false)
+				continue
+			}
+			call.Arguments = append(call.Arguments, stringArgument.Text)
+			call.Literal = append(call.Literal, true)
+		}
+
+		calls = append(calls, call)
+	})
+
+	return calls, nil
+}
+
+// walkNode walks a template's parse tree calling the given function on each command found
+func walkNode(node parse.Node, visitCommand func(*parse.CommandNode)) {
+	switch typedNode := node.(type) {
+	case *parse.ListNode:
+		if typedNode == nil {
+			return
+		}
+		for _, child := range typedNode.Nodes {
+			walkNode(child, visitCommand)
+		}
+	case *parse.ActionNode:
+		walkNode(typedNode.Pipe, visitCommand)
+	case *parse.IfNode:
+		walkBranchNode(&typedNode.BranchNode, visitCommand)
+	case *parse.RangeNode:
+		walkBranchNode(&typedNode.BranchNode, visitCommand)
+	case *parse.WithNode:
+		walkBranchNode(&typedNode.BranchNode, visitCommand)
+	case *parse.TemplateNode:
+		walkNode(typedNode.Pipe, visitCommand)
+	case *parse.PipeNode:
+		if typedNode == nil {
+			return
+		}
+		for _, command := range typedNode.Cmds {
+			walkNode(command, visitCommand)
+		}
+	case *parse.CommandNode:
+		visitCommand(typedNode)
+		for _, argument := range typedNode.Args {
+			walkNode(argument, visitCommand)
+		}
+	}
+}
+
+// walkBranchNode walks the pipeline and both lists of a branch node
+func walkBranchNode(node *parse.BranchNode, visitCommand func(*parse.CommandNode)) {
+	walkNode(node.Pipe, visitCommand)
+	walkNode(node.List, visitCommand)
+	walkNode(node.ElseList, visitCommand)
+}
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// This file contains functions designed to work with Kubernetes objects inside templates.
+// They exist to avoid re-implementing the same date arithmetic and parsing on every condition
+
+// GetClockFunctions return the time-related template functions measuring time from the given instant.
+// Processing loops pass their own instant, so every object in a loop is judged against the same time
+func GetClockFunctions(instant time.Time) template.FuncMap {
+	return template.FuncMap{
+		"now": func() time.Time {
+			return instant
+		},
+		"ago": func(timestamp interface{}) string {
+			return ago(instant, timestamp)
+		},
+		"age": func(object map[string]interface{}) (time.Duration, error) {
+			return age(instant, object)
+		},
+		"ageOf": func(timestamp interface{}) (time.Duration, error) {
+			return ageOf(instant, timestamp)
+		},
+		"olderThan": func(duration string, object map[string]interface{}) (bool, error) {
+			return olderThan(instant, duration, object)
+		},
+	}
+}
+
+// ago return the time passed from the given timestamp until the given instant, rounded to seconds.
+// It replaces the one from Sprig, which measures from the wall clock
+//
+// This is designed to be called from a template.
+func ago(instant time.Time, timestamp interface{}) string {
+	parsedTimestamp := instant
+
+	switch value := timestamp.(type) {
+	case time.Time:
+		parsedTimestamp = value
+	case int64:
+		parsedTimestamp = time.Unix(value, 0)
+	case int:
+		parsedTimestamp = time.Unix(int64(value), 0)
+	}
+
+	return instant.Sub(parsedTimestamp).Round(time.Second).String()
+}
+
+// age return the time passed from the creation of a Kubernetes object until the given instant.
+// It returns zero when the object has no creation timestamp
+//
+// This is designed to be called from a template.
+func age(instant time.Time, object map[string]interface{}) (time.Duration, error) {
+	creationTimestamp, found, err := unstructured.NestedString(object, "metadata", "creationTimestamp")
+	if err != nil {
+		return 0, fmt.Errorf("error reading metadata.creationTimestamp: %s", err.Error())
+	}
+
+	if !found || creationTimestamp == "" {
+		return 0, nil
+	}
+
+	return ageOf(instant, creationTimestamp)
+}
+
+// ageOf return the time passed from the given timestamp until the given instant.
+// The timestamp can be an RFC3339 string (as found in Kubernetes objects), a time.Time or a Unix epoch
+//
+// This is designed to be called from a template.
+func ageOf(instant time.Time, timestamp interface{}) (time.Duration, error) {
+	var parsedTimestamp time.Time
+
+	switch typedTimestamp := timestamp.(type) {
+	case string:
+		var err error
+		parsedTimestamp, err = time.Parse(time.RFC3339, typedTimestamp)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing timestamp '%s': %s", typedTimestamp, err.Error())
+		}
+	case time.Time:
+		parsedTimestamp = typedTimestamp
+	case *time.Time:
+		parsedTimestamp = *typedTimestamp
+	case int:
+		parsedTimestamp = time.Unix(int64(typedTimestamp), 0)
+	case int64:
+		parsedTimestamp = time.Unix(typedTimestamp, 0)
+	case float64:
+		parsedTimestamp = time.Unix(int64(typedTimestamp), 0)
+	default:
+		return 0, fmt.Errorf("unsupported timestamp type '%T'", timestamp)
+	}
+
+	return instant.Sub(parsedTimestamp), nil
+}
+
+// olderThan return true when the object was created longer ago than the given duration.
+// Duration is expressed in Golang format, such as '90s', '10m' or '2h'.
+// Parameters are ordered to allow pipelines like: .object | olderThan "2h"
+//
+// This is designed to be called from a template.
+func olderThan(instant time.Time, duration string, object map[string]interface{}) (bool, error) {
+	parsedDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return false, fmt.Errorf("error parsing duration '%s': %s", duration, err.Error())
+	}
+
+	objectAge, err := age(instant, object)
+	if err != nil {
+		return false, err
+	}
+
+	return objectAge > parsedDuration, nil
+}
+
+// parseQuantity converts a Kubernetes resource quantity such as '500m' or '2Gi' into a Quantity object.
+// A pointer is returned as methods like .Value or .MilliValue are defined over it
+//
+// This is designed to be called from a template.
+func parseQuantity(quantity interface{}) (*resource.Quantity, error) {
+	var parsedQuantity resource.Quantity
+	var err error
+
+	switch typedQuantity := quantity.(type) {
+	case resource.Quantity:
+		parsedQuantity = typedQuantity
+	case *resource.Quantity:
+		parsedQuantity = *typedQuantity
+	case string:
+		parsedQuantity, err = resource.ParseQuantity(typedQuantity)
+	default:
+		parsedQuantity, err = resource.ParseQuantity(fmt.Sprintf("%v", quantity))
+	}
+
+	return &parsedQuantity, err
+}
+
+// compareQuantity compares two Kubernetes resource quantities.
+// It returns -1 when 'a' is lower than 'b', 0 when they are equal, and 1 when 'a' is greater than 'b'
+//
+// This is designed to be called from a template.
+func compareQuantity(a, b interface{}) (int, error) {
+	parsedA, err := parseQuantity(a)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing quantity '%v': %s", a, err.Error())
+	}
+
+	parsedB, err := parseQuantity(b)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing quantity '%v': %s", b, err.Error())
+	}
+
+	return parsedA.Cmp(*parsedB), nil
+}
+
+// hasCondition return true when the object has a condition of the given type inside 'status.conditions'
+// and its status is the given one. For example: hasCondition .object "Ready" "False"
+//
+// This is designed to be called from a template.
+func hasCondition(object map[string]interface{}, conditionType string, conditionStatus string) bool {
+	conditions, found, err := unstructured.NestedSlice(object, "status", "conditions")
+	if err != nil || !found {
+		return false
+	}
+
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if conditionMap["type"] == conditionType && conditionMap["status"] == conditionStatus {
+			return true
+		}
+	}
+
+	return false
+}
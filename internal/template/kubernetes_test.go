@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		quantity  interface{}
+		wantValue int64
+		wantError bool
+	}{
+		{"binary suffix", "1Ki", 1024, false},
+		{"decimal suffix", "1k", 1000, false},
+		{"binary gibibytes", "1Gi", 1073741824, false},
+		{"decimal gigabytes", "1G", 1000000000, false},
+		{"millicores are rounded up", "500m", 1, false},
+		{"number", 3, 3, false},
+		{"invalid suffix", "1Gb", 0, true},
+		{"not a quantity", "lots", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quantity, err := parseQuantity(test.quantity)
+			if test.wantError {
+				if err == nil {
+					t.Fatalf("got quantity %s, want an error", quantity.String())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if quantity.Value() != test.wantValue {
+				t.Fatalf("got %d, want %d", quantity.Value(), test.wantValue)
+			}
+		})
+	}
+}
+
+func TestCompareQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		template  string
+		want      string
+		wantError string
+	}{
+		{"binary is greater than decimal", `{{ compareQuantity "1Gi" "1G" }}`, "1", ""},
+		{"decimal is lower than binary", `{{ compareQuantity "1M" "1Mi" }}`, "-1", ""},
+		{"equal in different units", `{{ compareQuantity "1024Ki" "1Mi" }}`, "0", ""},
+		{"cpu", `{{ compareQuantity "500m" "0.5" }}`, "0", ""},
+		{"parsed quantities", `{{ compareQuantity (parseQuantity "2") "1" }}`, "1", ""},
+		{"invalid first quantity", `{{ compareQuantity "lots" "1" }}`, "", "error parsing quantity 'lots'"},
+		{"invalid second quantity", `{{ compareQuantity "1" "1Gb" }}`, "", "error parsing quantity '1Gb'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := EvaluateTemplate(test.template, &map[string]interface{}{})
+			if test.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantError) {
+					t.Fatalf("got error %v, want one containing '%s'", err, test.wantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if result != test.want {
+				t.Fatalf("got %s, want %s", result, test.want)
+			}
+		})
+	}
+}
+
+func TestHasCondition(t *testing.T) {
+	pod := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+				map[string]interface{}{"type": "PodScheduled", "status": "True"},
+				"not a condition",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		object   map[string]interface{}
+		template string
+		want     string
+	}{
+		{"matching status", pod, `{{ hasCondition .object "Ready" "False" }}`, "true"},
+		{"different status", pod, `{{ hasCondition .object "Ready" "True" }}`, "false"},
+		{"status is case sensitive", pod, `{{ hasCondition .object "PodScheduled" "true" }}`, "false"},
+		{"missing condition", pod, `{{ hasCondition .object "Initialized" "True" }}`, "false"},
+		{"missing status.conditions", map[string]interface{}{"status": map[string]interface{}{}},
+			`{{ hasCondition .object "Ready" "False" }}`, "false"},
+		{"missing status", map[string]interface{}{}, `{{ hasCondition .object "Ready" "False" }}`, "false"},
+		{"malformed status.conditions", map[string]interface{}{"status": map[string]interface{}{"conditions": "Ready"}},
+			`{{ hasCondition .object "Ready" "False" }}`, "false"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := EvaluateTemplate(test.template, &map[string]interface{}{"object": test.object})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if result != test.want {
+				t.Fatalf("got %s, want %s", result, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2026 Alby Hernández <hola@achetronic.com>
+// SPDX-License-Identifier: Apache-2.0
+
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+const (
+	// Snippets can include others. This prevents infinite recursion
+	maxIncludeDepth = 100
+)
+
+var (
+	// Named snippets callable from every template with 'include'. They are parsed once, when they change
+	snippets          map[string]string
+	snippetTrees      map[string]*parse.Tree
+	snippetTreesMutex sync.RWMutex
+)
+
+// ParseSnippets parses a set of named snippets. Snippets can also define other named templates.
+// Functions are not checked, as some of them are only defined on evaluation time
+func ParseSnippets(namedSnippets map[string]string) (trees map[string]*parse.Tree, err error) {
+	trees = map[string]*parse.Tree{}
+
+	for name, snippet := range namedSnippets {
+		tree := parse.New(name)
+		tree.Mode = parse.SkipFuncCheck
+
+		_, err = tree.Parse(snippet, "", "", trees)
+		if err != nil {
+			return trees, fmt.Errorf("error parsing template '%s': %s", name, err.Error())
+		}
+	}
+
+	return trees, nil
+}
+
+// SetSnippets replaces the named snippets available for every template. They are only parsed when they change
+func SetSnippets(namedSnippets map[string]string) (err error) {
+	snippetTreesMutex.Lock()
+	defer snippetTreesMutex.Unlock()
+
+	if snippetTrees != nil && reflect.DeepEqual(namedSnippets, snippets) {
+		return nil
+	}
+
+	trees, err := ParseSnippets(namedSnippets)
+	if err != nil {
+		return err
+	}
+
+	snippets = namedSnippets
+	snippetTrees = trees
+	return nil
+}
+
+// addSnippets adds the named snippets to a template, and the 'include' function to execute them.
+// Parsed trees are shared between templates, as they are not modified on execution
+func addSnippets(parsedTemplate *template.Template) (err error) {
+	snippetTreesMutex.RLock()
+	defer snippetTreesMutex.RUnlock()
+
+	for name, tree := range snippetTrees {
+		_, err = parsedTemplate.AddParseTree(name, tree)
+		if err != nil {
+			return err
+		}
+	}
+
+	includeDepth := 0
+	parsedTemplate.Funcs(template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			if includeDepth >= maxIncludeDepth {
+				return "", fmt.Errorf("template '%s' exceeded max include depth of %d", name, maxIncludeDepth)
+			}
+
+			includeDepth++
+			defer func() { includeDepth-- }()
+
+			buffer := new(bytes.Buffer)
+			err := parsedTemplate.ExecuteTemplate(buffer, name, data)
+			return buffer.String(), err
+		},
+	})
+
+	return nil
+}